@@ -0,0 +1,67 @@
+package streambuf_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/itsmontoya/streambuf"
+	"github.com/itsmontoya/streambuf/streambuftest"
+)
+
+func Test_backend_conformance(t *testing.T) {
+	type testcase struct {
+		name    string
+		factory streambuftest.Factory
+	}
+
+	tests := []testcase{
+		{
+			name: "memory",
+			factory: func(t *testing.T) (w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+				return streambuf.NewMemoryBackend()
+			},
+		},
+		{
+			name: "chunks",
+			factory: func(t *testing.T) (w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+				return streambuf.NewMemoryBackend(streambuf.WithChunkSize(4))
+			},
+		},
+		{
+			name: "file",
+			factory: func(t *testing.T) (w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+				return newFileBackend(t, filepath.Join(t.TempDir(), "buffer.tmp"))
+			},
+		},
+		{
+			name: "file mmap",
+			factory: func(t *testing.T) (w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+				return newFileBackend(t, filepath.Join(t.TempDir(), "buffer.tmp"), streambuf.WithMmap())
+			},
+		},
+		{
+			name: "segments",
+			factory: func(t *testing.T) (w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+				opt := streambuf.WithSegments(streambuf.SegmentConfig{MaxBytes: 32})
+				return newFileBackend(t, filepath.Join(t.TempDir(), "segments"), opt)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streambuftest.TestBackend(t, tt.factory)
+		})
+	}
+}
+
+func newFileBackend(t *testing.T, path string, opts ...streambuf.Option) (w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	t.Helper()
+
+	var err error
+	if w, r, err = streambuf.NewFileBackend(path, opts...); err != nil {
+		t.Fatal(err)
+	}
+
+	return w, r
+}
@@ -1,55 +1,326 @@
 package streambuf
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net"
+	"os"
+	"time"
+)
+
+const (
+	// readFromBufferSize is the buffer ReadFrom reads non-file sources into.
+	readFromBufferSize = 64 << 10
+	// readFromChunkSize bounds each kernel copy of a regular file source, so
+	// readers are woken as the copy progresses.
+	readFromChunkSize = 8 << 20
 )
 
 // New constructs a new file Buffer.
-func New(filepath string) (out *Buffer, err error) {
-	var w writable
-	if w, err = newWritableFile(filepath); err != nil {
+// WithSegments treats filepath as a directory of segment files, and WithSync
+// applies a durability policy.
+func New(filepath string, opts ...Option) (out *Buffer, err error) {
+	c := newConfig(opts)
+	if err = c.validateFor(segmentsOption | mmapOption | syncOption); err != nil {
+		return nil, err
+	}
+
+	c.defaultCursorPath(filepath)
+
+	var (
+		w WritableBackend
+		r ReadableBackend
+	)
+
+	if w, r, err = newFileOrSegmentedBackend(filepath, c); err != nil {
 		return nil, err
 	}
 
-	var r readable
-	if r, err = newReadableFile(filepath); err != nil {
+	return newBuffer(w, r, c)
+}
+
+// NewWithBackend constructs a Buffer over custom storage.
+// w and r must share storage and satisfy the WritableBackend and
+// ReadableBackend contracts; the streambuftest package provides a conformance
+// suite. WithSync and WithRetention apply to custom backends.
+func NewWithBackend(w WritableBackend, r ReadableBackend, opts ...Option) (out *Buffer, err error) {
+	if w == nil || r == nil {
+		return nil, ErrNilBackend
+	}
+
+	c := newConfig(opts)
+	if err = c.validateFor(syncOption); err != nil {
 		return nil, err
 	}
 
-	return newWithBackend(w, r), nil
+	return newBuffer(w, r, c)
+}
+
+// NewFileBackend opens the file storage used by New without constructing a Buffer,
+// so it can be wrapped before being passed to NewWithBackend.
+// WithSegments treats filepath as a directory of segment files.
+func NewFileBackend(filepath string, opts ...Option) (w WritableBackend, r ReadableBackend, err error) {
+	c := newConfig(opts)
+	if err = c.validateFor(segmentsOption | mmapOption); err != nil {
+		return nil, nil, err
+	}
+
+	return newFileOrSegmentedBackend(filepath, c)
+}
+
+// NewMemoryBackend constructs the memory storage used by NewMemory without
+// constructing a Buffer, so it can be wrapped before being passed to NewWithBackend.
+// WithChunkSize selects chunked storage.
+func NewMemoryBackend(opts ...Option) (w WritableBackend, r ReadableBackend) {
+	c := newConfig(opts)
+	c.warnInvalid(memoryOption)
+	return newMemoryBackend(c)
+}
+
+// newMemoryBackend constructs the contiguous or chunked memory storage selected by c.
+func newMemoryBackend(c *config) (w WritableBackend, r ReadableBackend) {
+	if c.chunkSize > 0 {
+		wc := newWritableChunks(c.chunkSize, c.ringCapacity)
+		return wc, newReadableChunks(wc.c)
+	}
+
+	wm := newWritableMemory(c.initialCapacity, c.ringCapacity)
+	return wm, newReadableMemory(wm.m)
+}
+
+// Open opens or creates a file Buffer that journals a checksum for every write
+// in a sidecar file, so a restarted process can safely continue the same stream.
+// Existing bytes are validated against the journal; bytes after the last
+// confirmed write form a torn tail that is handled according to mode.
+// A file without a journal, such as one created by New, is adopted whole.
+// A low-water mark persisted by Truncate is restored.
+// WithSegments is not supported and returns ErrUnsupportedOption.
+func Open(filepath string, mode RecoveryMode, opts ...Option) (out *Buffer, rec Recovery, err error) {
+	c := newConfig(opts)
+	if err = c.validateFor(mmapOption | syncOption); err != nil {
+		return nil, rec, err
+	}
+
+	c.defaultCursorPath(filepath)
+
+	var (
+		statErr error
+		adopt   bool
+	)
+
+	_, statErr = os.Stat(filepath + journalExt)
+	adopt = errors.Is(statErr, os.ErrNotExist)
+
+	var j *journal
+	if j, err = newJournal(filepath, c.perm); err != nil {
+		return nil, rec, err
+	}
+
+	if rec, err = newRecovery(filepath, j, mode, adopt, c.perm); err != nil {
+		_ = j.close()
+		return nil, rec, err
+	}
+
+	var (
+		w WritableBackend
+		r ReadableBackend
+	)
+
+	if w, r, err = newFileBackend(filepath, c); err != nil {
+		_ = j.close()
+		return nil, rec, err
+	}
+
+	w = newWritableJournal(w, j, rec.Length)
+	if out, err = newBuffer(w, r, c); err != nil {
+		return nil, rec, err
+	}
+
+	out.raiseLowWaterMark(rec.LowWaterMark)
+	return out, rec, nil
+}
+
+// NewSynced constructs a file Buffer that syncs written bytes to stable storage
+// according to policy. When policy.CommittedReads is set, readers only observe
+// bytes that have been synced, so acknowledged data is never read ahead of
+// its durability. It is equivalent to New with WithSync(policy).
+func NewSynced(filepath string, policy SyncPolicy, opts ...Option) (out *Buffer, err error) {
+	return New(filepath, append(opts[:len(opts):len(opts)], WithSync(policy))...)
+}
+
+// NewSegmented constructs a file Buffer that spans a directory of numbered
+// segment files, rolling over to a new segment according to cfg.
+// Existing segments within dir are resumed, so a restarted process keeps
+// appending to the same stream. Old segments may be archived or removed
+// without affecting readers positioned in later segments.
+// It is equivalent to New with WithSegments(cfg).
+func NewSegmented(dir string, cfg SegmentConfig, opts ...Option) (out *Buffer, err error) {
+	return New(dir, append(opts[:len(opts):len(opts)], WithSegments(cfg))...)
 }
 
 // NewMemory constructs a new in-memory Buffer.
-func NewMemory() (out *Buffer) {
-	w := newWritableMemory(nil)
-	r := newReadableMemory(w.m)
-	return newWithBackend(w, r)
+func NewMemory(opts ...Option) (out *Buffer) {
+	c := newConfig(opts)
+	c.warnInvalid(memoryOption)
+	w, r := newMemoryBackend(c)
+	return newWithBackend(w, r, c)
 }
 
-func newWithBackend(w writable, r readable) (out *Buffer) {
+// NewMemoryRing constructs an in-memory Buffer that retains at most capacity bytes.
+// Once capacity is reached, the oldest bytes are discarded while offsets keep
+// increasing, so reads below the retained window return an *OffsetEvictedError.
+// The low-water mark follows the oldest retained byte.
+// A capacity less than or equal to 0 retains every byte, matching NewMemory.
+// It is equivalent to NewMemory with WithRingCapacity(capacity).
+func NewMemoryRing(capacity int64, opts ...Option) (out *Buffer) {
+	return NewMemory(append(opts[:len(opts):len(opts)], WithRingCapacity(capacity))...)
+}
+
+func newWithBackend(w WritableBackend, r ReadableBackend, c *config) (out *Buffer) {
 	var b Buffer
 	b.w = w
-	b.stream = newStreamWithReadable(r)
+	b.stream = newStreamWithReadable(r, c)
+	b.cursors = newCursors(c.cursorPath, c.perm)
+	if c.backpressure > 0 {
+		b.writers = make(chan struct{}, 1)
+	}
+
+	if c.retention != nil {
+		b.ret = newRetention(&b, c.retention, c.retentionInterval)
+	}
+
 	return &b
 }
 
+// newBuffer applies the configured sync policy to a backend and constructs the Buffer.
+func newBuffer(w WritableBackend, r ReadableBackend, c *config) (out *Buffer, err error) {
+	if c.sync == nil {
+		return newWithBackend(w, r, c), nil
+	}
+
+	var size int64
+	if size, err = r.Size(); err != nil {
+		_ = w.Close()
+		_ = r.Close()
+		return nil, err
+	}
+
+	ws := newWritableSync(w, *c.sync, size)
+	if c.sync.CommittedReads {
+		r = newReadableCommitted(r, ws)
+	}
+
+	out = newWithBackend(ws, r, c)
+	if c.sync.Mode == SyncEveryInterval {
+		out.syncer = newPeriodic("sync", c.sync.Interval, c.logger, func(now time.Time) (err error) {
+			return out.Sync()
+		})
+	}
+
+	return out, nil
+}
+
+// newFileOrSegmentedBackend opens a segmented backend when WithSegments is set
+// and a single file backend otherwise.
+func newFileOrSegmentedBackend(filepath string, c *config) (w WritableBackend, r ReadableBackend, err error) {
+	if c.segments != nil {
+		return newSegmentedBackend(filepath, c)
+	}
+
+	return newFileBackend(filepath, c)
+}
+
+// newFileBackend opens the writable and readable handles for a single file.
+func newFileBackend(filepath string, c *config) (w WritableBackend, r ReadableBackend, err error) {
+	var wf *writableFile
+	if wf, err = newWritableFile(filepath, c.perm); err != nil {
+		return nil, nil, err
+	}
+
+	if r, err = newFileReadable(filepath, c); err != nil {
+		_ = wf.Close()
+		return nil, nil, err
+	}
+
+	return wf, r, nil
+}
+
+// newFileReadable opens the readable handle for a single file, memory mapped
+// when WithMmap is set.
+func newFileReadable(filepath string, c *config) (r ReadableBackend, err error) {
+	if c.mmap {
+		return newReadableMmap(filepath)
+	}
+
+	var f *readableFile
+	if f, err = newReadableFile(filepath); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// newSegmentedBackend opens the writable and readable backends for a segment directory.
+func newSegmentedBackend(dir string, c *config) (w WritableBackend, r ReadableBackend, err error) {
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, nil, fmt.Errorf("create segment directory: %w", err)
+	}
+
+	var s *segments
+	if s, err = newSegments(dir, c.perm); err != nil {
+		return nil, nil, err
+	}
+
+	if w, err = newWritableSegments(s, *c.segments); err != nil {
+		_ = s.close()
+		return nil, nil, err
+	}
+
+	return w, newReadableSegments(s), nil
+}
+
 // Buffer is a thread-safe byte buffer with reader support.
 type Buffer struct {
 	*stream
 
-	w writable
+	w       WritableBackend
+	ret     *retention
+	syncer  *periodic
+	cursors *cursors
+
+	// writers holds one slot that writers take for the backpressure check and
+	// the write that follows it. It is nil unless WithBackpressure is set.
+	writers chan struct{}
 }
 
 // Write appends bytes to the buffer and wakes waiting readers.
+// With WithBackpressure, it first blocks while the slowest reader lags too far behind.
 // It returns ErrIsClosed if the buffer has been closed.
 func (b *Buffer) Write(bs []byte) (n int, err error) {
+	return b.WriteContext(context.Background(), bs)
+}
+
+// WriteContext behaves like Write but returns ctx.Err() if ctx is done while
+// blocked by backpressure. Nothing is written in that case.
+func (b *Buffer) WriteContext(ctx context.Context, bs []byte) (n int, err error) {
+	var release func()
+	if release, err = b.awaitReaders(ctx); err != nil {
+		return 0, err
+	}
+	defer release()
+
 	b.mux.RLock()
 	defer b.mux.RUnlock()
 	if n, err = b.w.Write(bs); err != nil {
 		return n, err
 	}
 
+	b.c.metrics.Written(n)
+	b.raiseEvicted()
+
 	if err = b.waiter.Refresh(); err != nil {
 		return n, err
 	}
@@ -57,16 +328,315 @@ func (b *Buffer) Write(bs []byte) (n int, err error) {
 	return n, err
 }
 
+// WriteBuffers appends every slice in bufs as one unit and wakes waiting
+// readers once. Memory backends make the whole unit visible to readers at
+// once, and file Buffers from New append the slices with writev on Linux.
+// Other backends receive the slices joined into a single Write, which copies them.
+// It returns ErrIsClosed if the buffer has been closed.
+func (b *Buffer) WriteBuffers(bufs net.Buffers) (n int64, err error) {
+	var release func()
+	if release, err = b.awaitReaders(context.Background()); err != nil {
+		return 0, err
+	}
+	defer release()
+
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	if bw, ok := b.w.(buffersWriter); ok {
+		n, err = bw.writeBuffers(bufs)
+	} else {
+		var written int
+		written, err = b.w.Write(bytes.Join(bufs, nil))
+		n = int64(written)
+	}
+
+	if err != nil {
+		return n, err
+	}
+
+	b.c.metrics.Written(int(n))
+	b.raiseEvicted()
+	return n, b.waiter.Refresh()
+}
+
+// ReadFrom appends bytes read from src until io.EOF, which is not returned as
+// an error, waking waiting readers once per appended chunk. When src is a
+// regular file and the Buffer writes straight to a file, the kernel copies
+// the bytes with copy_file_range where supported. Other sources, including
+// sockets, are read through a buffer outside the Buffer's locks, so a stalled
+// source never delays Close.
+func (b *Buffer) ReadFrom(src io.Reader) (n int64, err error) {
+	if fc, ok := b.w.(fileCopier); ok {
+		if f, ok := src.(*os.File); ok && isRegularFile(f) {
+			return b.copyFrom(fc, f)
+		}
+	}
+
+	buf := make([]byte, readFromBufferSize)
+	for {
+		read, readErr := src.Read(buf)
+		if read > 0 {
+			var written int
+			written, err = b.Write(buf[:read])
+			n += int64(written)
+			if err != nil {
+				return n, err
+			}
+		}
+
+		switch {
+		case errors.Is(readErr, io.EOF):
+			return n, nil
+		case readErr != nil:
+			return n, readErr
+		}
+	}
+}
+
+// copyFrom appends src through fc in chunks until src is exhausted.
+func (b *Buffer) copyFrom(fc fileCopier, src *os.File) (n int64, err error) {
+	var (
+		release func()
+		written int64
+	)
+
+	for {
+		if release, err = b.awaitReaders(context.Background()); err != nil {
+			return n, err
+		}
+
+		written, err = b.copyChunk(fc, src)
+		release()
+		n += written
+		if err != nil || written == 0 {
+			return n, err
+		}
+	}
+}
+
+// copyChunk appends one chunk of src through fc and wakes waiting readers.
+func (b *Buffer) copyChunk(fc fileCopier, src *os.File) (written int64, err error) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	if written, err = fc.copyFrom(src, readFromChunkSize); written > 0 {
+		b.c.metrics.Written(int(written))
+		if refreshErr := b.waiter.Refresh(); err == nil {
+			err = refreshErr
+		}
+	}
+
+	return written, err
+}
+
+// Begin starts a transaction whose writes are staged until Tx.Commit appends
+// them as one unit. It returns ErrIsClosed if the buffer has been closed.
+func (b *Buffer) Begin() (tx *Tx, err error) {
+	if b.isClosed() {
+		return nil, ErrIsClosed
+	}
+
+	return newTx(b), nil
+}
+
+// commit appends bs while holding the buffer exclusively, hiding the bytes
+// from readers until the write completes.
+func (b *Buffer) commit(bs []byte) (err error) {
+	var release func()
+	if release, err = b.awaitReaders(context.Background()); err != nil {
+		return err
+	}
+	defer release()
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.closed {
+		return ErrIsClosed
+	}
+
+	if len(bs) == 0 {
+		return nil
+	}
+
+	var start int64
+	if start, err = b.r.Size(); err != nil {
+		return err
+	}
+
+	var n int
+	b.beginCommit(start)
+	n, err = b.w.Write(bs)
+	b.endCommit()
+	if err != nil {
+		return err
+	}
+
+	b.c.metrics.Written(n)
+	b.raiseEvicted()
+	return b.waiter.Refresh()
+}
+
+// raiseEvicted raises the low-water mark past bytes the backend discarded on
+// its own, such as the oldest bytes of a memory ring.
+func (b *Buffer) raiseEvicted() {
+	if e, ok := b.w.(evicter); ok {
+		b.raiseLowWaterMark(e.evicted())
+	}
+}
+
+// awaitReaders takes the writer slot and blocks until the slowest open reader
+// lags no more than the backpressure limit. The caller writes and then calls
+// release, so concurrent writers cannot all pass the check before any of them
+// writes. Readers are waited on without holding the buffer lock, so
+// CloseAndWait and reader Close always release blocked writers.
+func (b *Buffer) awaitReaders(ctx context.Context) (release func(), err error) {
+	if b.advanced == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case b.writers <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	release = func() { <-b.writers }
+	if err = b.awaitLag(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// awaitLag blocks until the slowest open reader lags no more than the
+// backpressure limit. The caller must hold the writer slot.
+func (b *Buffer) awaitLag(ctx context.Context) (err error) {
+	for {
+		// The channel is taken before the check so an advance in between is not missed.
+		advanced := b.advanced.Wait()
+		if b.isClosed() {
+			return ErrIsClosed
+		}
+
+		var size int64
+		if size, err = b.Size(); err != nil {
+			return err
+		}
+
+		offset, ok := b.readers.slowest(b.LowWaterMark())
+		if !ok || size-offset <= b.c.backpressure {
+			return nil
+		}
+
+		select {
+		case <-advanced:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Sync flushes written bytes to stable storage and wakes readers that are
+// restricted to committed bytes. It is a no-op for memory backends.
+// It returns ErrIsClosed if the buffer has been closed.
+func (b *Buffer) Sync() (err error) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	if b.closed {
+		return ErrIsClosed
+	}
+
+	if err = b.w.Sync(); err != nil {
+		return err
+	}
+
+	return b.waiter.Refresh()
+}
+
+// Retain discards the head of the buffer according to policy, evaluated every interval.
+// Bytes before the resulting low-water mark are reclaimed by the backend and
+// reads below the mark return an *OffsetEvictedError. A previous policy is
+// replaced, and retention stops when the buffer is closed.
+func (b *Buffer) Retain(policy RetentionPolicy, interval time.Duration) (err error) {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.closed {
+		return ErrIsClosed
+	}
+
+	if b.ret != nil {
+		_ = b.ret.Close()
+	}
+
+	b.ret = newRetention(b, policy, interval)
+	return nil
+}
+
+// Truncate discards the bytes before offset and raises the low-water mark.
+// Offsets beyond the current size are clamped to the size, and offsets at or
+// below the current low-water mark are a no-op.
+func (b *Buffer) Truncate(offset int64) (err error) {
+	b.mux.RLock()
+	defer b.mux.RUnlock()
+	if b.closed {
+		return ErrIsClosed
+	}
+
+	var size int64
+	if size, err = b.Size(); err != nil {
+		return err
+	}
+
+	if !b.raiseLowWaterMark(min(offset, size)) {
+		return nil
+	}
+
+	return b.w.Truncate(b.LowWaterMark())
+}
+
+// NamedReader returns a StreamingReader that starts at the offset last
+// committed under name, or at 0 for a new name. File Buffers persist committed
+// offsets in a sidecar file next to the data (see WithCursorPath), so they
+// survive restarts; memory Buffers keep them for the Buffer's lifetime.
+// If the committed offset has been evicted, the reader starts at the
+// low-water mark. Readers sharing a name share its committed offset.
+// It returns ErrIsClosed if the buffer is closed.
+func (b *Buffer) NamedReader(name string) (r *NamedReader, err error) {
+	var (
+		offset int64
+		rd     *reader
+	)
+
+	if offset, err = b.cursors.get(name); err != nil {
+		return nil, err
+	}
+
+	if rd, err = b.openReader(true); err != nil {
+		return nil, err
+	}
+
+	if _, err = rd.Seek(offset, io.SeekStart); errors.Is(err, ErrOffsetEvicted) {
+		b.c.logger.Warn("streambuf: committed offset evicted", "cursor", name, "error", err)
+	}
+
+	return newNamedReader(rd, name, b.cursors, b.c.autoCommit), nil
+}
+
 // StreamingReader returns a new io.ReadSeekCloser that tracks its own read offset,
-// supports seeking relative to the start or current position, and waits for
+// supports seeking relative to the start, current position, or end, and waits for
 // future writes when the current end is reached.
 // It returns ErrIsClosed if the buffer is closed.
 func (b *Buffer) StreamingReader() (r io.ReadSeekCloser, err error) {
-	if err = b.checkoutReader(); err != nil {
+	var rd *reader
+	if rd, err = b.openReader(true); err != nil {
 		return nil, err
 	}
 
-	return newReader(b.stream, true), nil
+	return rd, nil
 }
 
 // Close closes the writer side of the buffer and signals waiting readers.
@@ -91,6 +661,16 @@ func (b *Buffer) CloseAndWait(ctx context.Context) (err error) {
 
 	b.closed = true
 
+	if b.ret != nil {
+		_ = b.ret.Close()
+	}
+
+	if b.syncer != nil {
+		_ = b.syncer.Close()
+	}
+
+	b.stopReaderTasks()
+
 	if err = b.w.Close(); err != nil {
 		return err
 	}
@@ -5,7 +5,10 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -944,3 +947,1469 @@ func Test_Buffer_CloseAndWait_underlying_reader_closed(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewMemoryRing(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		capacity int64
+		opts     []Option
+		writes   []string
+		offset   int64
+
+		want       string
+		wantOldest int64
+		wantErr    error
+	}
+
+	tests := []testcase{
+		{
+			name:     "under capacity",
+			capacity: 16,
+			writes:   []string{"hello", " world"},
+			offset:   0,
+			want:     "hello world",
+		},
+		{
+			name:     "evicted offset",
+			capacity: 8,
+			writes:   []string{"hello", " world"},
+			offset:   0,
+
+			want:       "lo world",
+			wantOldest: 3,
+			wantErr:    ErrOffsetEvicted,
+		},
+		{
+			name:     "chunked evicted offset",
+			capacity: 8,
+			opts:     []Option{WithChunkSize(4)},
+			writes:   []string{"hello", " world"},
+			offset:   0,
+
+			want:       "lo world",
+			wantOldest: 3,
+			wantErr:    ErrOffsetEvicted,
+		},
+		{
+			name:     "oldest retained offset",
+			capacity: 8,
+			writes:   []string{"hello", " world"},
+			offset:   3,
+
+			want:       "lo world",
+			wantOldest: 3,
+		},
+		{
+			name:     "single write larger than capacity",
+			capacity: 4,
+			writes:   []string{"hello world"},
+			offset:   7,
+
+			want:       "orld",
+			wantOldest: 7,
+		},
+		{
+			name:     "unbounded capacity",
+			capacity: 0,
+			writes:   []string{"hello", " world"},
+			offset:   0,
+			want:     "hello world",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				b       *Buffer
+				r       io.ReadSeekCloser
+				got     []byte
+				evicted *OffsetEvictedError
+				err     error
+			)
+
+			b = NewMemoryRing(tt.capacity, tt.opts...)
+			t.Cleanup(func() {
+				_ = b.Close()
+			})
+
+			for _, w := range tt.writes {
+				if _, err = b.Write([]byte(w)); err != nil {
+					t.Fatalf("Write() unexpected error: %v", err)
+				}
+			}
+
+			if r, err = b.Reader(); err != nil {
+				t.Fatalf("Reader() unexpected error: %v", err)
+			}
+
+			t.Cleanup(func() {
+				_ = r.Close()
+			})
+
+			if b.LowWaterMark() != tt.wantOldest {
+				t.Fatalf("LowWaterMark() invalid value, expected <%v> and received <%v>", tt.wantOldest, b.LowWaterMark())
+			}
+
+			_, err = r.Seek(tt.offset, io.SeekStart)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Seek() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.As(err, &evicted) {
+					t.Fatalf("Seek() invalid error type, expected <%T> and received <%T>", evicted, err)
+				}
+
+				if evicted.Oldest != tt.wantOldest {
+					t.Fatalf("Seek() invalid oldest offset, expected <%v> and received <%v>", tt.wantOldest, evicted.Oldest)
+				}
+			}
+
+			if got, err = io.ReadAll(r); err != nil {
+				t.Fatalf("ReadAll() unexpected error: %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Fatalf("ReadAll() invalid value, expected <%v> and received <%v>", tt.want, string(got))
+			}
+		})
+	}
+}
+
+func Test_NewSegmented(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		cfg    SegmentConfig
+		writes []string
+
+		wantSegments int
+	}
+
+	tests := []testcase{
+		{
+			name:         "no roll triggers",
+			writes:       []string{"hello", " ", "world"},
+			wantSegments: 1,
+		},
+		{
+			name:         "roll by size",
+			cfg:          SegmentConfig{MaxBytes: 6},
+			writes:       []string{"hello", " ", "world"},
+			wantSegments: 2,
+		},
+		{
+			name:         "oversized write is not split",
+			cfg:          SegmentConfig{MaxBytes: 2},
+			writes:       []string{"hello", " world"},
+			wantSegments: 2,
+		},
+		{
+			name:         "roll by age",
+			cfg:          SegmentConfig{MaxAge: time.Nanosecond},
+			writes:       []string{"hello", " ", "world"},
+			wantSegments: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				dir   string
+				b     *Buffer
+				r     io.ReadSeekCloser
+				bases []int64
+				got   []byte
+				err   error
+			)
+
+			dir = t.TempDir()
+			if b, err = NewSegmented(dir, tt.cfg); err != nil {
+				t.Fatalf("NewSegmented() unexpected error: %v", err)
+			}
+
+			t.Cleanup(func() {
+				_ = b.Close()
+			})
+
+			for _, w := range tt.writes {
+				time.Sleep(time.Millisecond)
+				if _, err = b.Write([]byte(w)); err != nil {
+					t.Fatalf("Write() unexpected error: %v", err)
+				}
+			}
+
+			if bases, err = scanSegments(dir); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(bases) != tt.wantSegments {
+				t.Fatalf("NewSegmented() invalid segment count, expected <%v> and received <%v>", tt.wantSegments, len(bases))
+			}
+
+			if r, err = b.Reader(); err != nil {
+				t.Fatalf("Reader() unexpected error: %v", err)
+			}
+
+			t.Cleanup(func() {
+				_ = r.Close()
+			})
+
+			if got, err = io.ReadAll(r); err != nil {
+				t.Fatalf("ReadAll() unexpected error: %v", err)
+			}
+
+			if string(got) != "hello world" {
+				t.Fatalf("ReadAll() invalid value, expected <%v> and received <%v>", "hello world", string(got))
+			}
+		})
+	}
+}
+
+func Test_NewSegmented_reopen(t *testing.T) {
+	var (
+		dir string
+		b   *Buffer
+		r   io.ReadSeekCloser
+		got []byte
+		err error
+	)
+
+	dir = t.TempDir()
+	if b, err = NewSegmented(dir, SegmentConfig{MaxBytes: 5}); err != nil {
+		t.Fatalf("NewSegmented() unexpected error: %v", err)
+	}
+
+	for _, w := range []string{"hello", " ", "world"} {
+		if _, err = b.Write([]byte(w)); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+	}
+
+	if err = b.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	// Removing the oldest segment simulates archiving it while the stream lives on.
+	if err = os.Remove(dir + "/" + segmentName(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err = NewSegmented(dir, SegmentConfig{MaxBytes: 5}); err != nil {
+		t.Fatalf("NewSegmented() unexpected error on reopen: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	if _, err = b.Write([]byte("!")); err != nil {
+		t.Fatalf("Write() unexpected error after reopen: %v", err)
+	}
+
+	if r, err = b.Reader(); err != nil {
+		t.Fatalf("Reader() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = r.Close()
+	})
+
+	if _, err = r.Read(make([]byte, 1)); !errors.Is(err, ErrOffsetEvicted) {
+		t.Fatalf("Read() invalid error for removed segment, expected <%v> and received <%v>", ErrOffsetEvicted, err)
+	}
+
+	if _, err = r.Seek(5, io.SeekStart); err != nil {
+		t.Fatalf("Seek() unexpected error: %v", err)
+	}
+
+	if got, err = io.ReadAll(r); err != nil {
+		t.Fatalf("ReadAll() unexpected error: %v", err)
+	}
+
+	if string(got) != " world!" {
+		t.Fatalf("ReadAll() invalid value, expected <%v> and received <%v>", " world!", string(got))
+	}
+}
+
+func Test_NewSegmented_reopen_MaxAge(t *testing.T) {
+	var (
+		dir   string
+		b     *Buffer
+		bases []int64
+		err   error
+	)
+
+	dir = t.TempDir()
+	cfg := SegmentConfig{MaxAge: 50 * time.Millisecond}
+	if b, err = NewSegmented(dir, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = b.Write([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = b.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A recent modification must not make an old segment look new.
+	time.Sleep(60 * time.Millisecond)
+	now := time.Now()
+	if err = os.Chtimes(dir+"/"+segmentName(0), now, now); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err = NewSegmented(dir, cfg); err != nil {
+		t.Fatalf("NewSegmented() unexpected error on reopen: %v", err)
+	}
+	defer b.Close()
+
+	if _, err = b.Write([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+
+	if bases, err = scanSegments(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bases) != 2 {
+		t.Fatalf("scanSegments() invalid count, expected <2> and received <%d>", len(bases))
+	}
+}
+
+func Test_NewSegmented_Size_concurrent(t *testing.T) {
+	var (
+		b    *Buffer
+		r    io.ReadSeekCloser
+		wg   sync.WaitGroup
+		size int64
+		err  error
+	)
+
+	if b, err = NewSegmented(t.TempDir(), SegmentConfig{MaxBytes: 64}); err != nil {
+		t.Fatalf("NewSegmented() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	if r, err = b.Reader(); err != nil {
+		t.Fatalf("Reader() unexpected error: %v", err)
+	}
+	defer r.Close()
+
+	const writes = 1000
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < writes; i++ {
+			if _, err := b.Write([]byte("a")); err != nil {
+				t.Errorf("Write() unexpected error: %v", err)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < writes; i++ {
+		if _, err = b.Size(); err != nil {
+			t.Fatalf("Size() unexpected error: %v", err)
+		}
+
+		if _, err = r.Seek(0, io.SeekEnd); err != nil {
+			t.Fatalf("Seek() unexpected error: %v", err)
+		}
+	}
+
+	wg.Wait()
+	if size, err = b.Size(); err != nil {
+		t.Fatalf("Size() unexpected error: %v", err)
+	}
+
+	if size != writes {
+		t.Fatalf("Size() invalid value, expected <%v> and received <%v>", writes, size)
+	}
+}
+
+func Test_Buffer_Truncate(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		init func(t *testing.T) (b *Buffer, err error)
+
+		offset int64
+
+		wantLowWaterMark int64
+	}
+
+	tests := []testcase{
+		{
+			name: "memory",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				t.Helper()
+				return NewMemory(), nil
+			},
+			offset:           6,
+			wantLowWaterMark: 6,
+		},
+		{
+			name: "file",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				t.Helper()
+				return New(t.TempDir() + "/buffer-truncate.tmp")
+			},
+			offset:           6,
+			wantLowWaterMark: 6,
+		},
+		{
+			name: "segmented",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				t.Helper()
+				return NewSegmented(t.TempDir(), SegmentConfig{MaxBytes: 6})
+			},
+			offset:           6,
+			wantLowWaterMark: 6,
+		},
+		{
+			name: "offset beyond size",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				t.Helper()
+				return NewMemory(), nil
+			},
+			offset:           64,
+			wantLowWaterMark: 11,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				b   *Buffer
+				r   io.ReadSeekCloser
+				pos int64
+				got []byte
+				err error
+			)
+
+			if b, err = tt.init(t); err != nil {
+				t.Fatal(err)
+			}
+
+			t.Cleanup(func() {
+				_ = b.Close()
+			})
+
+			for _, w := range []string{"hello ", "world"} {
+				if _, err = b.Write([]byte(w)); err != nil {
+					t.Fatalf("Write() unexpected error: %v", err)
+				}
+			}
+
+			if r, err = b.Reader(); err != nil {
+				t.Fatalf("Reader() unexpected error: %v", err)
+			}
+
+			t.Cleanup(func() {
+				_ = r.Close()
+			})
+
+			if err = b.Truncate(tt.offset); err != nil {
+				t.Fatalf("Truncate() unexpected error: %v", err)
+			}
+
+			if got := b.LowWaterMark(); got != tt.wantLowWaterMark {
+				t.Fatalf("LowWaterMark() invalid value, expected <%v> and received <%v>", tt.wantLowWaterMark, got)
+			}
+
+			if _, err = r.Read(make([]byte, 1)); !errors.Is(err, ErrOffsetEvicted) {
+				t.Fatalf("Read() invalid error below low-water mark, expected <%v> and received <%v>", ErrOffsetEvicted, err)
+			}
+
+			pos, err = r.Seek(0, io.SeekStart)
+			if !errors.Is(err, ErrOffsetEvicted) {
+				t.Fatalf("Seek() invalid error below low-water mark, expected <%v> and received <%v>", ErrOffsetEvicted, err)
+			}
+
+			if pos != tt.wantLowWaterMark {
+				t.Fatalf("Seek() invalid position, expected <%v> and received <%v>", tt.wantLowWaterMark, pos)
+			}
+
+			if got, err = io.ReadAll(r); err != nil {
+				t.Fatalf("ReadAll() unexpected error: %v", err)
+			}
+
+			want := "hello world"[tt.wantLowWaterMark:]
+			if string(got) != want {
+				t.Fatalf("ReadAll() invalid value, expected <%v> and received <%v>", want, string(got))
+			}
+		})
+	}
+}
+
+func Test_Buffer_Retain(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		policy   RetentionPolicy
+		interval time.Duration
+
+		wantLowWaterMark int64
+		wantErr          error
+	}
+
+	tests := []testcase{
+		{
+			name:             "max bytes",
+			policy:           MaxBytes(5),
+			interval:         time.Millisecond,
+			wantLowWaterMark: 6,
+		},
+		{
+			name:             "max age",
+			policy:           MaxAge(time.Millisecond),
+			interval:         time.Millisecond,
+			wantLowWaterMark: 11,
+		},
+		{
+			name: "custom predicate",
+			policy: RetentionFunc(func(info RetentionInfo) (offset int64) {
+				return 2
+			}),
+			interval:         time.Millisecond,
+			wantLowWaterMark: 2,
+		},
+		{
+			name:     "invalid interval",
+			policy:   MaxBytes(5),
+			interval: 0,
+			wantErr:  ErrInvalidInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				b   *Buffer
+				err error
+			)
+
+			b = NewMemory()
+			t.Cleanup(func() {
+				_ = b.Close()
+			})
+
+			if _, err = b.Write([]byte("hello world")); err != nil {
+				t.Fatalf("Write() unexpected error: %v", err)
+			}
+
+			err = b.Retain(tt.policy, tt.interval)
+			if !isEqualErrors(err, tt.wantErr) {
+				t.Fatalf("Retain() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			deadline := time.Now().Add(time.Second)
+			for b.LowWaterMark() != tt.wantLowWaterMark {
+				if time.Now().After(deadline) {
+					t.Fatalf("LowWaterMark() invalid value, expected <%v> and received <%v>", tt.wantLowWaterMark, b.LowWaterMark())
+				}
+
+				time.Sleep(time.Millisecond)
+			}
+		})
+	}
+}
+
+func Test_Open(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		setup func(t *testing.T, filepath string)
+		mode  RecoveryMode
+		opts  []Option
+
+		want     string
+		wantRec  Recovery
+		wantErr  error
+		wantNext string
+	}
+
+	writeJournaled := func(t *testing.T, filepath string, writes ...string) {
+		var (
+			b   *Buffer
+			err error
+		)
+
+		t.Helper()
+
+		if b, _, err = Open(filepath, RecoverTruncate); err != nil {
+			t.Fatalf("setup Open() unexpected error: %v", err)
+		}
+
+		for _, w := range writes {
+			if _, err = b.Write([]byte(w)); err != nil {
+				t.Fatalf("setup Write() unexpected error: %v", err)
+			}
+		}
+
+		if err = b.Close(); err != nil {
+			t.Fatalf("setup Close() unexpected error: %v", err)
+		}
+	}
+
+	appendRaw := func(t *testing.T, filepath string, bs string) {
+		var (
+			f   *os.File
+			err error
+		)
+
+		t.Helper()
+
+		if f, err = os.OpenFile(filepath, os.O_APPEND|os.O_WRONLY, 0644); err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		if _, err = f.WriteString(bs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:     "new file",
+			setup:    func(t *testing.T, filepath string) {},
+			wantNext: "!",
+		},
+		{
+			name: "clean reopen",
+			setup: func(t *testing.T, filepath string) {
+				writeJournaled(t, filepath, "hello ", "world")
+			},
+			want:     "hello world",
+			wantRec:  Recovery{Length: 11},
+			wantNext: "hello world!",
+		},
+		{
+			name: "torn tail truncated",
+			setup: func(t *testing.T, filepath string) {
+				writeJournaled(t, filepath, "hello ", "world")
+				appendRaw(t, filepath, "tor")
+			},
+			mode:     RecoverTruncate,
+			want:     "hello world",
+			wantRec:  Recovery{Length: 11, Truncated: 3},
+			wantNext: "hello world!",
+		},
+		{
+			name: "torn tail reported",
+			setup: func(t *testing.T, filepath string) {
+				writeJournaled(t, filepath, "hello ", "world")
+				appendRaw(t, filepath, "tor")
+			},
+			mode:    RecoverReport,
+			wantRec: Recovery{Length: 11, Truncated: 3},
+			wantErr: ErrTornTail,
+		},
+		{
+			name: "checksum mismatch",
+			setup: func(t *testing.T, filepath string) {
+				writeJournaled(t, filepath, "hello ", "world")
+				if err := os.WriteFile(filepath, []byte("hello wXrld"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			mode:     RecoverTruncate,
+			want:     "hello ",
+			wantRec:  Recovery{Length: 6, Truncated: 5},
+			wantNext: "hello !",
+		},
+		{
+			name:    "segments unsupported",
+			setup:   func(t *testing.T, filepath string) {},
+			opts:    []Option{WithSegments(SegmentConfig{MaxBytes: 1 << 10})},
+			wantErr: ErrUnsupportedOption,
+		},
+		{
+			name: "unjournaled file is adopted",
+			setup: func(t *testing.T, filepath string) {
+				if err := os.WriteFile(filepath, []byte("hello world"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want:     "hello world",
+			wantRec:  Recovery{Length: 11},
+			wantNext: "hello world!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				filepath string
+				b        *Buffer
+				r        io.ReadSeekCloser
+				rec      Recovery
+				got      []byte
+				err      error
+			)
+
+			filepath = t.TempDir() + "/buffer-open.tmp"
+			tt.setup(t, filepath)
+
+			b, rec, err = Open(filepath, tt.mode, tt.opts...)
+			if !isEqualErrors(err, tt.wantErr) {
+				t.Fatalf("Open() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+			}
+
+			if rec != tt.wantRec {
+				t.Fatalf("Open() invalid recovery, expected <%+v> and received <%+v>", tt.wantRec, rec)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if r, err = b.Reader(); err != nil {
+				t.Fatalf("Reader() unexpected error: %v", err)
+			}
+
+			if got, err = io.ReadAll(r); err != nil {
+				t.Fatalf("ReadAll() unexpected error: %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Fatalf("ReadAll() invalid value, expected <%v> and received <%v>", tt.want, string(got))
+			}
+
+			if _, err = b.Write([]byte("!")); err != nil {
+				t.Fatalf("Write() unexpected error: %v", err)
+			}
+
+			if err = r.Close(); err != nil {
+				t.Fatalf("Close() unexpected error: %v", err)
+			}
+
+			if err = b.Close(); err != nil {
+				t.Fatalf("Close() unexpected error: %v", err)
+			}
+
+			if got, err = os.ReadFile(filepath); err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != tt.wantNext {
+				t.Fatalf("Write() invalid file contents, expected <%v> and received <%v>", tt.wantNext, string(got))
+			}
+
+			if b, rec, err = Open(filepath, RecoverReport); err != nil {
+				t.Fatalf("Open() unexpected error on reopen: %v", err)
+			}
+
+			t.Cleanup(func() {
+				_ = b.Close()
+			})
+
+			if rec.Length != int64(len(tt.wantNext)) {
+				t.Fatalf("Open() invalid recovered length on reopen, expected <%v> and received <%v>", len(tt.wantNext), rec.Length)
+			}
+		})
+	}
+}
+
+func Test_Open_compacts_journal(t *testing.T) {
+	var (
+		filepath string
+		b        *Buffer
+		f        *os.File
+		info     os.FileInfo
+		rec      Recovery
+		err      error
+	)
+
+	filepath = t.TempDir() + "/buffer-compact.tmp"
+	if b, _, err = Open(filepath, RecoverTruncate); err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+
+	for i := 0; i < journalCompactEntries; i++ {
+		if _, err = b.Write([]byte("a")); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+	}
+
+	if err = b.Sync(); err != nil {
+		t.Fatalf("Sync() unexpected error: %v", err)
+	}
+
+	if info, err = os.Stat(filepath + journalExt); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Size() != 0 {
+		t.Fatalf("Sync() invalid journal size, expected <%v> and received <%v>", 0, info.Size())
+	}
+
+	if _, err = b.Write([]byte("b")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if err = b.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	if f, err = os.OpenFile(filepath, os.O_APPEND|os.O_WRONLY, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = f.WriteString("tor"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, rec, err = Open(filepath, RecoverTruncate); err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	want := Recovery{Length: journalCompactEntries + 1, Truncated: 3}
+	if rec != want {
+		t.Fatalf("Open() invalid recovery, expected <%+v> and received <%+v>", want, rec)
+	}
+}
+
+func Test_Open_after_Truncate(t *testing.T) {
+	var (
+		filepath string
+		b        *Buffer
+		r        io.ReadSeekCloser
+		rec      Recovery
+		got      []byte
+		err      error
+	)
+
+	filepath = t.TempDir() + "/buffer-truncate.tmp"
+	if b, _, err = Open(filepath, RecoverReport); err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+
+	payload := bytes.Repeat([]byte("streambuf"), 1024)
+	for i := 0; i < 4; i++ {
+		if _, err = b.Write(payload); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+	}
+
+	size := int64(4 * len(payload))
+	low := size - 100
+	if err = b.Truncate(low); err != nil {
+		t.Fatalf("Truncate() unexpected error: %v", err)
+	}
+
+	if err = b.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	if b, rec, err = Open(filepath, RecoverReport); err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	want := Recovery{Length: size, LowWaterMark: low}
+	if rec != want {
+		t.Fatalf("Open() invalid recovery, expected <%+v> and received <%+v>", want, rec)
+	}
+
+	if b.LowWaterMark() != low {
+		t.Fatalf("LowWaterMark() invalid value, expected <%v> and received <%v>", low, b.LowWaterMark())
+	}
+
+	if r, err = b.Reader(); err != nil {
+		t.Fatalf("Reader() unexpected error: %v", err)
+	}
+	defer r.Close()
+
+	if _, err = r.Seek(low, io.SeekStart); err != nil {
+		t.Fatalf("Seek() unexpected error: %v", err)
+	}
+
+	if got, err = io.ReadAll(r); err != nil {
+		t.Fatalf("ReadAll() unexpected error: %v", err)
+	}
+
+	if !bytes.Equal(got, payload[len(payload)-100:]) {
+		t.Fatalf("ReadAll() invalid value, expected <%s> and received <%s>", payload[len(payload)-100:], got)
+	}
+}
+
+func Test_Buffer_StreamingReader_seek_end(t *testing.T) {
+	var (
+		b   *Buffer
+		r   io.ReadSeekCloser
+		bs  []byte
+		n   int
+		err error
+	)
+
+	b = NewMemory()
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	if _, err = b.Write([]byte("history")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if r, err = b.StreamingReader(); err != nil {
+		t.Fatalf("StreamingReader() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = r.Close()
+	})
+
+	if _, err = r.Seek(0, io.SeekEnd); err != nil {
+		t.Fatalf("Seek() unexpected error: %v", err)
+	}
+
+	if _, err = b.Write([]byte("future")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	bs = make([]byte, 16)
+	if n, err = r.Read(bs); err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+
+	if string(bs[:n]) != "future" {
+		t.Fatalf("Read() invalid value, expected <%v> and received <%v>", "future", string(bs[:n]))
+	}
+}
+
+func Test_NewSynced(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		policy SyncPolicy
+		writes []string
+
+		wantVisible string
+		wantErr     error
+	}
+
+	tests := []testcase{
+		{
+			name:        "sync never",
+			policy:      SyncPolicy{Mode: SyncNever, CommittedReads: true},
+			writes:      []string{"hello", " world"},
+			wantVisible: "",
+		},
+		{
+			name:        "sync every write",
+			policy:      SyncPolicy{Mode: SyncEveryWrite, CommittedReads: true},
+			writes:      []string{"hello", " world"},
+			wantVisible: "hello world",
+		},
+		{
+			name:        "sync every bytes",
+			policy:      SyncPolicy{Mode: SyncEveryBytes, Bytes: 5, CommittedReads: true},
+			writes:      []string{"hello", " wor"},
+			wantVisible: "hello",
+		},
+		{
+			name:        "uncommitted reads",
+			policy:      SyncPolicy{Mode: SyncNever},
+			writes:      []string{"hello", " world"},
+			wantVisible: "hello world",
+		},
+		{
+			name:    "missing byte threshold",
+			policy:  SyncPolicy{Mode: SyncEveryBytes},
+			wantErr: ErrInvalidSyncPolicy,
+		},
+		{
+			name:    "missing interval",
+			policy:  SyncPolicy{Mode: SyncEveryInterval},
+			wantErr: ErrInvalidInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				b   *Buffer
+				r   io.ReadSeekCloser
+				got []byte
+				err error
+			)
+
+			b, err = NewSynced(t.TempDir()+"/buffer-synced.tmp", tt.policy)
+			if !isEqualErrors(err, tt.wantErr) {
+				t.Fatalf("NewSynced() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			t.Cleanup(func() {
+				_ = b.Close()
+			})
+
+			for _, w := range tt.writes {
+				if _, err = b.Write([]byte(w)); err != nil {
+					t.Fatalf("Write() unexpected error: %v", err)
+				}
+			}
+
+			if r, err = b.Reader(); err != nil {
+				t.Fatalf("Reader() unexpected error: %v", err)
+			}
+
+			t.Cleanup(func() {
+				_ = r.Close()
+			})
+
+			if got, err = io.ReadAll(r); err != nil {
+				t.Fatalf("ReadAll() unexpected error: %v", err)
+			}
+
+			if string(got) != tt.wantVisible {
+				t.Fatalf("ReadAll() invalid visible bytes, expected <%v> and received <%v>", tt.wantVisible, string(got))
+			}
+
+			// An explicit Sync always commits every written byte.
+			if err = b.Sync(); err != nil {
+				t.Fatalf("Sync() unexpected error: %v", err)
+			}
+
+			if got, err = io.ReadAll(r); err != nil {
+				t.Fatalf("ReadAll() unexpected error after Sync(): %v", err)
+			}
+
+			want := strings.Join(tt.writes, "")
+			if tt.wantVisible+string(got) != want {
+				t.Fatalf("ReadAll() invalid bytes after Sync(), expected <%v> and received <%v>", want, tt.wantVisible+string(got))
+			}
+		})
+	}
+}
+
+func Test_NewSynced_interval(t *testing.T) {
+	var (
+		b   *Buffer
+		r   io.ReadSeekCloser
+		bs  []byte
+		n   int
+		err error
+	)
+
+	policy := SyncPolicy{Mode: SyncEveryInterval, Interval: 5 * time.Millisecond, CommittedReads: true}
+	if b, err = NewSynced(t.TempDir()+"/buffer-synced-interval.tmp", policy); err != nil {
+		t.Fatalf("NewSynced() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	if r, err = b.StreamingReader(); err != nil {
+		t.Fatalf("StreamingReader() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = r.Close()
+	})
+
+	if _, err = b.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	// The streaming reader is woken by the interval sync rather than the write.
+	bs = make([]byte, 5)
+	if n, err = r.Read(bs); err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+
+	if string(bs[:n]) != "hello" {
+		t.Fatalf("Read() invalid value, expected <%v> and received <%v>", "hello", string(bs[:n]))
+	}
+}
+
+func Test_Buffer_Size(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		init func(t *testing.T) (b *Buffer, err error)
+	}
+
+	tests := []testcase{
+		{
+			name: "memory",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				return NewMemory(), nil
+			},
+		},
+		{
+			name: "file",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				return New(t.TempDir() + "/buffer.tmp")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				b    *Buffer
+				size int64
+				err  error
+			)
+
+			if b, err = tt.init(t); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = b.Write([]byte("hello")); err != nil {
+				t.Fatal(err)
+			}
+
+			if size, err = b.Size(); err != nil {
+				t.Fatalf("Size() unexpected error: %v", err)
+			}
+
+			if size != 5 {
+				t.Fatalf("Size() invalid value, expected <5> and received <%d>", size)
+			}
+
+			if err = b.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = b.Size(); !errors.Is(err, ErrIsClosed) {
+				t.Fatalf("Size() invalid error, expected <%v> and received <%v>", ErrIsClosed, err)
+			}
+		})
+	}
+}
+
+func Test_Buffer_WriteContext_backpressure(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		// release unblocks a write held back by the slow reader r.
+		release func(b *Buffer, r io.ReadSeekCloser)
+
+		wantErr error
+	}
+
+	tests := []testcase{
+		{
+			name: "reader advances",
+			release: func(b *Buffer, r io.ReadSeekCloser) {
+				_, _ = r.Read(make([]byte, 8))
+			},
+		},
+		{
+			name: "reader seeks to end",
+			release: func(b *Buffer, r io.ReadSeekCloser) {
+				_, _ = r.Seek(0, io.SeekEnd)
+			},
+		},
+		{
+			name: "reader closes",
+			release: func(b *Buffer, r io.ReadSeekCloser) {
+				_ = r.Close()
+			},
+		},
+		{
+			name: "buffer closes",
+			release: func(b *Buffer, r io.ReadSeekCloser) {
+				_ = b.CloseAndWait(expiredContext)
+			},
+			wantErr: ErrIsClosed,
+		},
+		{
+			name:    "context canceled",
+			release: func(b *Buffer, r io.ReadSeekCloser) {},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewMemory(WithBackpressure(4))
+			defer b.Close()
+
+			r, err := b.StreamingReader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			if _, err = b.Write([]byte("01234567")); err != nil {
+				t.Fatalf("Write() unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := b.WriteContext(ctx, []byte("89"))
+				done <- err
+			}()
+
+			select {
+			case err = <-done:
+				t.Fatalf("WriteContext() expected to block, returned <%v>", err)
+			case <-time.After(20 * time.Millisecond):
+			}
+
+			tt.release(b, r)
+			if err = <-done; !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WriteContext() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func Test_Buffer_WriteContext_backpressure_concurrent(t *testing.T) {
+	const (
+		maxLag    = 64
+		writers   = 8
+		writeSize = 16
+	)
+
+	w, rb := NewMemoryBackend()
+	b, err := NewWithBackend(&slowWriter{WritableBackend: w}, rb, WithBackpressure(maxLag))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	r, err := b.StreamingReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := b.WriteContext(ctx, make([]byte, writeSize)); err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var size int64
+	if size, err = b.Size(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The reader never reads, so its lag is the buffer size.
+	if want := int64(maxLag + writeSize); size > want {
+		t.Fatalf("WriteContext() invalid lag, expected at most <%d> and received <%d>", want, size)
+	}
+}
+
+// slowWriter delays each write, widening the window between a backpressure
+// check and the write it admits.
+type slowWriter struct {
+	WritableBackend
+}
+
+func (w *slowWriter) Write(bs []byte) (n int, err error) {
+	time.Sleep(time.Millisecond)
+	return w.WritableBackend.Write(bs)
+}
+
+func Test_Buffer_WriteBuffers(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		init func(t *testing.T, m Metrics) (b *Buffer, err error)
+	}
+
+	tests := []testcase{
+		{
+			name: "memory",
+			init: func(t *testing.T, m Metrics) (b *Buffer, err error) {
+				return NewMemory(WithMetrics(m)), nil
+			},
+		},
+		{
+			name: "chunks",
+			init: func(t *testing.T, m Metrics) (b *Buffer, err error) {
+				return NewMemory(WithMetrics(m), WithChunkSize(4)), nil
+			},
+		},
+		{
+			name: "file",
+			init: func(t *testing.T, m Metrics) (b *Buffer, err error) {
+				return New(t.TempDir()+"/buffer.tmp", WithMetrics(m))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m testMetrics
+			b, err := tt.init(t, &m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer b.Close()
+
+			wake := b.waiter.Wait()
+			n, err := b.WriteBuffers(net.Buffers{[]byte("hello"), []byte(" "), []byte("world")})
+			if err != nil {
+				t.Fatalf("WriteBuffers() unexpected error: %v", err)
+			}
+
+			if n != 11 || m.written.Load() != 11 {
+				t.Fatalf("WriteBuffers() invalid count, expected <11> and received <%d> (metrics <%d>)", n, m.written.Load())
+			}
+
+			if !isClosedChan(wake) {
+				t.Fatal("WriteBuffers() expected waiting readers to be woken")
+			}
+
+			r, err := b.Reader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			got, err := io.ReadAll(r)
+			if err != nil || string(got) != "hello world" {
+				t.Fatalf("ReadAll() invalid result, expected <hello world> and received <%s> <%v>", got, err)
+			}
+
+			if err = b.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = b.WriteBuffers(net.Buffers{[]byte("x")}); !errors.Is(err, ErrIsClosed) {
+				t.Fatalf("WriteBuffers() invalid error, expected <%v> and received <%v>", ErrIsClosed, err)
+			}
+		})
+	}
+}
+
+func Test_Buffer_ReadFrom(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		init func(t *testing.T) (b *Buffer, err error)
+		// src returns the source holding "world".
+		src func(t *testing.T) (r io.Reader)
+	}
+
+	fileBuffer := func(t *testing.T) (b *Buffer, err error) {
+		return New(t.TempDir() + "/buffer.tmp")
+	}
+
+	memoryBuffer := func(t *testing.T) (b *Buffer, err error) {
+		return NewMemory(), nil
+	}
+
+	fileSource := func(t *testing.T) (r io.Reader) {
+		path := t.TempDir() + "/source.tmp"
+		if err := os.WriteFile(path, []byte("world"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() { _ = f.Close() })
+		return f
+	}
+
+	readerSource := func(t *testing.T) (r io.Reader) {
+		return strings.NewReader("world")
+	}
+
+	tests := []testcase{
+		{
+			name: "file from regular file",
+			init: fileBuffer,
+			src:  fileSource,
+		},
+		{
+			name: "file from reader",
+			init: fileBuffer,
+			src:  readerSource,
+		},
+		{
+			name: "memory from regular file",
+			init: memoryBuffer,
+			src:  fileSource,
+		},
+		{
+			name: "synced file from regular file",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				return NewSynced(t.TempDir()+"/buffer.tmp", SyncPolicy{Mode: SyncEveryWrite})
+			},
+			src: fileSource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.init(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer b.Close()
+
+			if _, err = b.Write([]byte("hello ")); err != nil {
+				t.Fatal(err)
+			}
+
+			sr, err := b.StreamingReader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sr.Close()
+
+			n, err := b.ReadFrom(tt.src(t))
+			if err != nil || n != 5 {
+				t.Fatalf("ReadFrom() invalid result, expected <5> and received <%d> <%v>", n, err)
+			}
+
+			got := make([]byte, 11)
+			if _, err = io.ReadFull(sr, got); err != nil || string(got) != "hello world" {
+				t.Fatalf("Read() invalid result, expected <hello world> and received <%s> <%v>", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package streambuf
+
+// buffersWriter is implemented by backends that can append several slices as
+// one unit without joining them first. No other write interleaves with the
+// slices, and memory backends make them visible to readers all at once.
+type buffersWriter interface {
+	// writeBuffers appends every slice in bufs in order.
+	writeBuffers(bufs [][]byte) (n int64, err error)
+}
@@ -0,0 +1,16 @@
+package streambuf
+
+var _ Codec[[]byte] = BytesCodec{}
+
+// BytesCodec passes raw byte slices through unchanged.
+type BytesCodec struct{}
+
+// Encode returns v unchanged.
+func (BytesCodec) Encode(v []byte) (bs []byte, err error) {
+	return v, nil
+}
+
+// Decode returns bs unchanged.
+func (BytesCodec) Decode(bs []byte) (v []byte, err error) {
+	return bs, nil
+}
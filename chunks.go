@@ -0,0 +1,146 @@
+package streambuf
+
+import (
+	"sync"
+)
+
+// DefaultChunkSize is the chunk size used when WithChunkSize receives a
+// non-positive size.
+const DefaultChunkSize = 64 << 10
+
+// newChunks constructs empty chunked storage with chunks of size bytes.
+func newChunks(size int) (out *chunks) {
+	var c chunks
+	c.size = size
+	return &c
+}
+
+// chunks stores bytes in fixed-size chunks so appends never copy existing data.
+// Every chunk except the last is full, so an offset maps directly to a chunk.
+type chunks struct {
+	mux sync.RWMutex
+
+	size int
+	list [][]byte
+	// start is the index of the first retained byte within list[0].
+	start int
+	// offset is the absolute stream offset of list[0][start].
+	offset int64
+	// length is the number of retained bytes.
+	length int64
+}
+
+// write appends each slice of bufs in order under a single lock, filling the
+// last chunk before allocating the next.
+func (c *chunks) write(bufs ...[]byte) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for _, in := range bufs {
+		c.length += int64(len(in))
+		for len(in) > 0 {
+			last := len(c.list) - 1
+			if last < 0 || len(c.list[last]) == c.size {
+				c.list = append(c.list, make([]byte, 0, c.size))
+				last++
+			}
+
+			n := min(c.size-len(c.list[last]), len(in))
+			c.list[last] = append(c.list[last], in[:n]...)
+			in = in[n:]
+		}
+	}
+}
+
+// readAt copies retained bytes from the absolute index into in, spanning
+// chunks as needed. ok is false when index precedes the retained bytes, in
+// which case low is the oldest retained offset.
+func (c *chunks) readAt(in []byte, index int64) (n int, low int64, ok bool) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	if index < c.offset {
+		return 0, c.offset, false
+	}
+
+	if index >= c.offset+c.length {
+		return 0, c.offset, true
+	}
+
+	rel := index - c.offset + int64(c.start)
+	i := int(rel / int64(c.size))
+	within := int(rel % int64(c.size))
+	for ; i < len(c.list) && n < len(in); i++ {
+		n += copy(in[n:], c.list[i][within:])
+		within = 0
+	}
+
+	return n, c.offset, true
+}
+
+// viewAt returns up to n retained bytes from the absolute index without
+// copying. The view ends at the chunk boundary. ok is false when index
+// precedes the retained bytes, in which case low is the oldest retained offset.
+func (c *chunks) viewAt(index int64, n int) (out []byte, low int64, ok bool) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	if index < c.offset {
+		return nil, c.offset, false
+	}
+
+	if index >= c.offset+c.length {
+		return nil, c.offset, true
+	}
+
+	rel := index - c.offset + int64(c.start)
+	chunk := c.list[rel/int64(c.size)]
+	start := int(rel % int64(c.size))
+	end := min(start+n, len(chunk))
+	return chunk[start:end:end], c.offset, true
+}
+
+// begin returns the absolute offset of the first retained byte.
+func (c *chunks) begin() (offset int64) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.offset
+}
+
+// end returns the absolute offset immediately after the last retained byte.
+func (c *chunks) end() (offset int64) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.offset + c.length
+}
+
+// evict discards the oldest bytes so at most capacity bytes are retained.
+func (c *chunks) evict(capacity int64) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.discard(c.length - capacity)
+}
+
+// truncate discards the retained bytes before the absolute offset.
+// Offsets at or below the current offset are a no-op.
+func (c *chunks) truncate(offset int64) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.discard(min(offset-c.offset, c.length))
+}
+
+// discard drops the oldest n bytes and releases chunks that become empty.
+// The caller must hold the write lock.
+func (c *chunks) discard(n int64) {
+	if n <= 0 {
+		return
+	}
+
+	c.offset += n
+	c.length -= n
+	c.start += int(n % int64(c.size))
+	drop := int(n/int64(c.size)) + c.start/c.size
+	c.start %= c.size
+	for i := range drop {
+		c.list[i] = nil
+	}
+
+	c.list = c.list[drop:]
+}
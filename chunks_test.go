@@ -0,0 +1,252 @@
+package streambuf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_chunks(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		// init applies writes and discards to c.
+		init func(c *chunks)
+
+		index int64
+
+		want    string
+		wantErr error
+	}
+
+	tests := []testcase{
+		{
+			name: "read within chunk",
+			init: func(c *chunks) {
+				c.write([]byte("abc"))
+			},
+			index: 1,
+			want:  "bc",
+		},
+		{
+			name: "read spans chunks",
+			init: func(c *chunks) {
+				c.write([]byte("ab"))
+				c.write([]byte("cdefghij"))
+			},
+			index: 2,
+			want:  "cdefghij",
+		},
+		{
+			name: "read at end",
+			init: func(c *chunks) {
+				c.write([]byte("abcd"))
+			},
+			index:   4,
+			wantErr: io.EOF,
+		},
+		{
+			name: "truncate within chunk",
+			init: func(c *chunks) {
+				c.write([]byte("abcdef"))
+				c.truncate(2)
+			},
+			index: 2,
+			want:  "cdef",
+		},
+		{
+			name: "truncate below retained",
+			init: func(c *chunks) {
+				c.write([]byte("abcdef"))
+				c.truncate(5)
+			},
+			index:   4,
+			wantErr: ErrOffsetEvicted,
+		},
+		{
+			name: "evict releases whole chunks",
+			init: func(c *chunks) {
+				c.write([]byte("abcdefghij"))
+				c.evict(3)
+			},
+			index: 7,
+			want:  "hij",
+		},
+		{
+			name: "write after discarding everything",
+			init: func(c *chunks) {
+				c.write([]byte("abcdefgh"))
+				c.truncate(8)
+				c.write([]byte("ij"))
+			},
+			index: 8,
+			want:  "ij",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newChunks(4)
+			tt.init(c)
+			r := newReadableChunks(c)
+
+			got := make([]byte, 16)
+			n, err := r.ReadAt(got, tt.index)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadAt() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+			}
+
+			if string(got[:n]) != tt.want {
+				t.Fatalf("ReadAt() invalid value, expected <%s> and received <%s>", tt.want, got[:n])
+			}
+		})
+	}
+}
+
+func Benchmark_memory_Write(b *testing.B) {
+	for _, size := range []int{64, 4096} {
+		for _, bc := range memoryBenchmarks() {
+			b.Run(fmt.Sprintf("%s/%d", bc.name, size), func(b *testing.B) {
+				w, _ := bc.init()
+				in := make([]byte, size)
+				latencies := make([]time.Duration, 0, b.N)
+				b.SetBytes(int64(size))
+				b.ResetTimer()
+				for range b.N {
+					start := time.Now()
+					if _, err := w.Write(in); err != nil {
+						b.Fatal(err)
+					}
+
+					latencies = append(latencies, time.Since(start))
+				}
+
+				b.StopTimer()
+				slices.Sort(latencies)
+				b.ReportMetric(float64(latencies[len(latencies)*99/100]), "p99-ns/op")
+			})
+		}
+	}
+}
+
+func Benchmark_memory_ReadAt(b *testing.B) {
+	for _, bc := range memoryBenchmarks() {
+		b.Run(bc.name, func(b *testing.B) {
+			w, r := bc.init()
+			if _, err := w.Write(make([]byte, 1<<20)); err != nil {
+				b.Fatal(err)
+			}
+
+			out := make([]byte, 4096)
+			b.SetBytes(int64(len(out)))
+			b.ResetTimer()
+			for i := range b.N {
+				if _, err := r.ReadAt(out, int64(i*len(out))%(1<<20)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// Benchmark_memory_ReadAt_growing reads in parallel while another goroutine
+// keeps appending, so reads contend with growth. The backend is replaced every
+// 64 MiB to bound memory.
+func Benchmark_memory_ReadAt_growing(b *testing.B) {
+	const (
+		blockSize   = 4096
+		growthLimit = 64 << 20
+	)
+
+	for _, bc := range memoryBenchmarks() {
+		b.Run(bc.name, func(b *testing.B) {
+			var current atomic.Value
+			in := make([]byte, blockSize)
+			reset := func() (w WritableBackend) {
+				var r ReadableBackend
+				w, r = bc.init()
+				if _, err := w.Write(in); err != nil {
+					b.Fatal(err)
+				}
+
+				current.Store(r)
+				return w
+			}
+
+			w := reset()
+			done := make(chan struct{})
+			stopped := make(chan struct{})
+			go func() {
+				defer close(stopped)
+				for written := blockSize; ; written += blockSize {
+					select {
+					case <-done:
+						return
+					default:
+					}
+
+					if written >= growthLimit {
+						w, written = reset(), blockSize
+					}
+
+					if _, err := w.Write(in); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			}()
+
+			b.SetBytes(blockSize)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				out := make([]byte, blockSize)
+				var i int64
+				for pb.Next() {
+					r := current.Load().(ReadableBackend)
+					size, err := r.Size()
+					if err != nil {
+						b.Error(err)
+						return
+					}
+
+					if _, err = r.ReadAt(out, (i*blockSize)%(size-size%blockSize)); err != nil {
+						b.Error(err)
+						return
+					}
+
+					i++
+				}
+			})
+
+			b.StopTimer()
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+type memoryBenchmark struct {
+	name string
+	init func() (w WritableBackend, r ReadableBackend)
+}
+
+func memoryBenchmarks() (out []memoryBenchmark) {
+	return []memoryBenchmark{
+		{
+			name: "slice",
+			init: func() (w WritableBackend, r ReadableBackend) {
+				return NewMemoryBackend()
+			},
+		},
+		{
+			name: "chunks",
+			init: func() (w WritableBackend, r ReadableBackend) {
+				return NewMemoryBackend(WithChunkSize(DefaultChunkSize))
+			},
+		},
+	}
+}
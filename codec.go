@@ -0,0 +1,7 @@
+package streambuf
+
+// Codec converts values of T to and from record payloads for a TypedBuffer.
+type Codec[T any] interface {
+	Encode(v T) (bs []byte, err error)
+	Decode(bs []byte) (v T, err error)
+}
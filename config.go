@@ -0,0 +1,104 @@
+package streambuf
+
+import (
+	"log/slog"
+	"os"
+	"time"
+)
+
+// DefaultRetentionInterval is the retention interval used when WithRetention
+// receives a non-positive interval.
+const DefaultRetentionInterval = time.Second
+
+// DefaultPollInterval is how often a follow Stream stats its file when
+// WithPollInterval is not set or receives a non-positive interval.
+const DefaultPollInterval = 250 * time.Millisecond
+
+// newConfig constructs a config with defaults and applies opts in order.
+func newConfig(opts []Option) (out *config) {
+	var c config
+	c.perm = 0644
+	c.initialCapacity = 1024
+	c.pollInterval = DefaultPollInterval
+	c.logger = slog.New(slog.DiscardHandler)
+	c.metrics = nopMetrics{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c
+}
+
+// config holds the settings collected from Options.
+type config struct {
+	perm            os.FileMode
+	initialCapacity int
+	ringCapacity    int64
+	chunkSize       int
+
+	mmap     bool
+	segments *SegmentConfig
+	sync     *SyncPolicy
+
+	retention         RetentionPolicy
+	retentionInterval time.Duration
+
+	pollInterval time.Duration
+
+	cursorPath string
+	autoCommit time.Duration
+
+	slowConsumer *SlowConsumerPolicy
+	backpressure int64
+
+	logger  *slog.Logger
+	metrics Metrics
+
+	// kinds records the storage-specific options that were set.
+	kinds optionKind
+}
+
+// defaultCursorPath places named reader cursors next to the data at filepath
+// unless WithCursorPath was set.
+func (c *config) defaultCursorPath(filepath string) {
+	if c.cursorPath == "" {
+		c.cursorPath = filepath + cursorsExt
+	}
+}
+
+// validate returns an error for settings that cannot be applied.
+func (c *config) validate() (err error) {
+	if c.sync != nil {
+		if err = c.sync.validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.slowConsumer != nil {
+		return c.slowConsumer.validate()
+	}
+
+	return nil
+}
+
+// validateFor returns an error for settings that cannot be applied, including
+// storage-specific options outside supported.
+func (c *config) validateFor(supported optionKind) (err error) {
+	if err = c.validate(); err != nil {
+		return err
+	}
+
+	if c.kinds&^supported != 0 {
+		return ErrUnsupportedOption
+	}
+
+	return nil
+}
+
+// warnInvalid logs settings that validateFor rejects, for constructors that do
+// not return an error. Invalid settings are skipped by those constructors.
+func (c *config) warnInvalid(supported optionKind) {
+	if err := c.validateFor(supported); err != nil {
+		c.logger.Warn("streambuf: invalid option ignored", "error", err)
+	}
+}
@@ -0,0 +1,37 @@
+package streambuf
+
+import "context"
+
+// newConsumer constructs a member of g.
+func newConsumer(g *ConsumerGroup) (out *Consumer) {
+	var c Consumer
+	c.g = g
+	c.closer = newWaiter()
+	return &c
+}
+
+// Consumer is one member of a ConsumerGroup.
+type Consumer struct {
+	g *ConsumerGroup
+
+	closer *waiter
+}
+
+// Receive returns the next record for this member, waiting until one is
+// written, a delivered record is nacked or exceeds its visibility timeout,
+// ctx is done, or the consumer or group is closed. Once the Buffer is closed
+// and every record is acknowledged, Receive returns io.EOF.
+func (c *Consumer) Receive(ctx context.Context) (m *Message, err error) {
+	return c.g.receive(ctx, c)
+}
+
+// Close leaves the group. Records delivered to this member and not yet
+// acknowledged become available to other members immediately.
+func (c *Consumer) Close() (err error) {
+	if err = c.closer.Close(); err != nil {
+		return err
+	}
+
+	c.g.leave(c)
+	return nil
+}
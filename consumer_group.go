@@ -0,0 +1,336 @@
+package streambuf
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// NewConsumerGroup constructs a work queue over the records of rb. Each
+// record is delivered to exactly one member at a time; a record that is not
+// acknowledged within visibility, or that is nacked, is redelivered.
+// The group's committed offset, the end of the longest run of acknowledged
+// records, is stored like a NamedReader cursor under name, so a restarted
+// group resumes after it. Records acknowledged out of order beyond the
+// committed offset are redelivered after a restart (at-least-once delivery).
+// An open group does not hold Buffer.CloseAndWait; only its members do.
+func NewConsumerGroup(rb *RecordBuffer, name string, visibility time.Duration) (out *ConsumerGroup, err error) {
+	var (
+		g      ConsumerGroup
+		offset int64
+	)
+
+	if visibility <= 0 {
+		return nil, ErrInvalidInterval
+	}
+
+	g.rb = rb
+	g.name = name
+	g.visibility = visibility
+	if offset, err = rb.b.cursors.get(name); err != nil {
+		return nil, err
+	}
+
+	// The group's own reader is detached, so only members hold CloseAndWait.
+	var br *reader
+	if br, err = rb.b.openDetachedReader(true); err != nil {
+		return nil, err
+	}
+
+	g.rr = newRecordReader(br, rb.checksum)
+
+	if _, err = g.rr.Seek(offset, io.SeekStart); errors.Is(err, ErrOffsetEvicted) {
+		rb.b.c.logger.Warn("streambuf: committed offset evicted", "group", name, "error", err)
+	}
+
+	g.committed = g.rr.Offset()
+	g.fetched = make(chan *delivery)
+	g.changed = newWaiter()
+	go g.fetch()
+	return &g, nil
+}
+
+// ConsumerGroup distributes the records of a RecordBuffer across its members.
+type ConsumerGroup struct {
+	mux sync.Mutex
+
+	rb         *RecordBuffer
+	name       string
+	visibility time.Duration
+
+	// rr reads records in order on behalf of every member.
+	rr *RecordReader
+	// inflight holds fetched records in offset order until acknowledged.
+	inflight []*delivery
+	// committed is the offset after the longest acknowledged prefix.
+	committed int64
+	// fetched hands each new record to the first waiting member.
+	fetched chan *delivery
+	// changed wakes waiting members when records become redeliverable.
+	changed *waiter
+	// ended is set once the RecordBuffer has no more records to fetch.
+	ended bool
+
+	closed bool
+}
+
+// Consumer adds a member to the group. Members count as Buffer readers, so
+// CloseAndWait waits for them to close.
+// It returns ErrIsClosed if the group or Buffer is closed.
+func (g *ConsumerGroup) Consumer() (c *Consumer, err error) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if g.closed {
+		return nil, ErrIsClosed
+	}
+
+	if err = g.rb.b.checkoutReader(); err != nil {
+		return nil, err
+	}
+
+	return newConsumer(g), nil
+}
+
+// Committed returns the offset after the longest run of acknowledged records.
+func (g *ConsumerGroup) Committed() (offset int64) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	return g.committed
+}
+
+// Close stops delivering records. Pending Receive calls return ErrIsClosed,
+// and members should still be closed.
+func (g *ConsumerGroup) Close() (err error) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if g.closed {
+		return ErrIsClosed
+	}
+
+	g.closed = true
+	_ = g.changed.Close()
+	return g.rr.Close()
+}
+
+// fetch reads records in order and offers each to the members.
+func (g *ConsumerGroup) fetch() {
+	var (
+		d   *delivery
+		err error
+	)
+
+	for {
+		d = &delivery{offset: g.rr.Offset()}
+		switch d.data, err = g.rr.ReadRecord(); {
+		case err == nil:
+		case errors.Is(err, ErrCorruptRecord) && g.rr.Offset() == d.offset:
+			// A corrupt header cannot be skipped, so nothing more can be fetched.
+			g.end()
+			return
+		case errors.Is(err, ErrCorruptRecord):
+			// Corrupt records are skipped, so they count as acknowledged.
+			d.acked = true
+		default:
+			g.end()
+			return
+		}
+
+		d.end = g.rr.Offset()
+		if !g.track(d) {
+			continue
+		}
+
+		if !g.offer(d) {
+			return
+		}
+	}
+}
+
+// offer blocks until a member takes d or the group closes. Wake-ups meant for
+// redelivery are ignored.
+func (g *ConsumerGroup) offer(d *delivery) (ok bool) {
+	for {
+		select {
+		case g.fetched <- d:
+			return true
+		case <-g.changed.Wait():
+			if g.isClosed() {
+				return false
+			}
+		}
+	}
+}
+
+// track appends d to the inflight records and reports whether it awaits delivery.
+func (g *ConsumerGroup) track(d *delivery) (deliverable bool) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.inflight = append(g.inflight, d)
+	if d.acked {
+		g.advance()
+		return false
+	}
+
+	return true
+}
+
+// end records that no more records will be fetched and wakes waiting members.
+func (g *ConsumerGroup) end() {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.ended = true
+	_ = g.changed.Refresh()
+}
+
+// receive delivers the oldest redeliverable record or the next new record to c.
+func (g *ConsumerGroup) receive(ctx context.Context, c *Consumer) (m *Message, err error) {
+	for {
+		var (
+			fetched <-chan *delivery
+			changed <-chan struct{}
+			timer   <-chan time.Time
+		)
+
+		if m, fetched, changed, timer, err = g.poll(c); m != nil || err != nil {
+			return m, err
+		}
+
+		select {
+		case d := <-fetched:
+			g.mux.Lock()
+			m = d.deliver(g, c, time.Now())
+			g.mux.Unlock()
+			return m, nil
+		case <-changed:
+		case <-timer:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.closer.Wait():
+			return nil, ErrIsClosed
+		}
+	}
+}
+
+// poll delivers a redeliverable record to c when one is due. Otherwise it
+// returns the channels to wait on: new records, changes, and the next expiry.
+func (g *ConsumerGroup) poll(c *Consumer) (m *Message, fetched <-chan *delivery, changed <-chan struct{}, timer <-chan time.Time, err error) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	switch {
+	case g.closed:
+		return nil, nil, nil, nil, ErrIsClosed
+	case isClosedChan(c.closer.Wait()):
+		return nil, nil, nil, nil, ErrIsClosed
+	}
+
+	now := time.Now()
+	var next time.Time
+	for _, d := range g.inflight {
+		switch {
+		case d.redeliverable(now):
+			return d.deliver(g, c, now), nil, nil, nil, nil
+		case d.acked || d.attempts == 0:
+		case next.IsZero() || d.deadline.Before(next):
+			next = d.deadline
+		}
+	}
+
+	if g.ended && len(g.inflight) == 0 {
+		return nil, nil, nil, nil, io.EOF
+	}
+
+	if !next.IsZero() {
+		timer = time.After(next.Sub(now))
+	}
+
+	if !g.ended {
+		fetched = g.fetched
+	}
+
+	return nil, fetched, g.changed.Wait(), timer, nil
+}
+
+// ack marks d as processed and advances the committed offset if attempt is
+// still its current delivery.
+func (g *ConsumerGroup) ack(d *delivery, attempt int) (err error) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	switch {
+	case g.closed:
+		return ErrIsClosed
+	case d.acked:
+		return nil
+	case d.attempts != attempt:
+		return ErrStaleDelivery
+	}
+
+	d.acked = true
+	return g.advance()
+}
+
+// nack makes d due for redelivery if attempt is still its current delivery.
+func (g *ConsumerGroup) nack(d *delivery, attempt int) (err error) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if g.closed {
+		return ErrIsClosed
+	}
+
+	switch {
+	case d.acked:
+		return nil
+	case d.attempts != attempt:
+		return ErrStaleDelivery
+	}
+
+	d.deadline = time.Time{}
+	return g.changed.Refresh()
+}
+
+// leave makes the records held by c due for redelivery and releases its
+// reader checkout.
+func (g *ConsumerGroup) leave(c *Consumer) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	for _, d := range g.inflight {
+		if d.owner == c && !d.acked {
+			d.deadline = time.Time{}
+		}
+	}
+
+	if !g.closed {
+		_ = g.changed.Refresh()
+	}
+
+	g.rb.b.checkinReader()
+}
+
+// advance drops the acknowledged prefix of inflight and persists the new
+// committed offset. The caller must hold the lock.
+func (g *ConsumerGroup) advance() (err error) {
+	var n int
+	for n < len(g.inflight) && g.inflight[n].acked {
+		g.committed = g.inflight[n].end
+		n++
+	}
+
+	if n == 0 {
+		return nil
+	}
+
+	g.inflight = g.inflight[n:]
+	if g.ended && len(g.inflight) == 0 {
+		_ = g.changed.Refresh()
+	}
+
+	return g.rb.b.cursors.commit(g.name, g.committed)
+}
+
+// isClosed reports whether Close has been called.
+func (g *ConsumerGroup) isClosed() (closed bool) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	return g.closed
+}
@@ -0,0 +1,341 @@
+package streambuf
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_ConsumerGroup(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		// run receives with c and returns the payloads in delivery order.
+		run func(t *testing.T, g *ConsumerGroup, c *Consumer) (got []string)
+
+		want          []string
+		wantCommitted int64
+	}
+
+	receive := func(t *testing.T, c *Consumer) (m *Message) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		m, err := c.Receive(ctx)
+		if err != nil {
+			t.Fatalf("Receive() unexpected error: %v", err)
+		}
+
+		return m
+	}
+
+	tests := []testcase{
+		{
+			name: "ack in order",
+			run: func(t *testing.T, g *ConsumerGroup, c *Consumer) (got []string) {
+				for i := 0; i < 3; i++ {
+					m := receive(t, c)
+					got = append(got, string(m.Data))
+					if err := m.Ack(); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				return got
+			},
+			want:          []string{"a", "b", "c"},
+			wantCommitted: 6,
+		},
+		{
+			name: "nack redelivers",
+			run: func(t *testing.T, g *ConsumerGroup, c *Consumer) (got []string) {
+				m := receive(t, c)
+				if err := m.Nack(); err != nil {
+					t.Fatal(err)
+				}
+
+				m = receive(t, c)
+				if m.Attempt != 2 {
+					t.Fatalf("Receive() invalid attempt, expected <2> and received <%d>", m.Attempt)
+				}
+
+				return append(got, string(m.Data))
+			},
+			want:          []string{"a"},
+			wantCommitted: 0,
+		},
+		{
+			name: "visibility timeout redelivers",
+			run: func(t *testing.T, g *ConsumerGroup, c *Consumer) (got []string) {
+				first := receive(t, c)
+				got = append(got, string(first.Data))
+				for i := 0; i < 2; i++ {
+					m := receive(t, c)
+					got = append(got, string(m.Data))
+					if err := m.Ack(); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				// The first record is redelivered once its visibility expires.
+				m := receive(t, c)
+				if m.Offset != first.Offset || m.Attempt != 2 {
+					t.Fatalf("Receive() invalid redelivery, expected offset <%d> and received <%d> (attempt %d)", first.Offset, m.Offset, m.Attempt)
+				}
+
+				if err := m.Ack(); err != nil {
+					t.Fatal(err)
+				}
+
+				return append(got, string(m.Data))
+			},
+			want:          []string{"a", "b", "c", "a"},
+			wantCommitted: 6,
+		},
+		{
+			name: "ack after redelivery",
+			run: func(t *testing.T, g *ConsumerGroup, c *Consumer) (got []string) {
+				first := receive(t, c)
+				got = append(got, string(first.Data))
+				for i := 0; i < 2; i++ {
+					m := receive(t, c)
+					got = append(got, string(m.Data))
+					if err := m.Ack(); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				m := receive(t, c)
+				got = append(got, string(m.Data))
+				if err := first.Ack(); !errors.Is(err, ErrStaleDelivery) {
+					t.Fatalf("Ack() invalid error, expected <%v> and received <%v>", ErrStaleDelivery, err)
+				}
+
+				if err := first.Nack(); !errors.Is(err, ErrStaleDelivery) {
+					t.Fatalf("Nack() invalid error, expected <%v> and received <%v>", ErrStaleDelivery, err)
+				}
+
+				if committed := g.Committed(); committed != 0 {
+					t.Fatalf("Committed() invalid value after a stale Ack, expected <0> and received <%d>", committed)
+				}
+
+				if err := m.Ack(); err != nil {
+					t.Fatal(err)
+				}
+
+				return got
+			},
+			want:          []string{"a", "b", "c", "a"},
+			wantCommitted: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				g   *ConsumerGroup
+				c   *Consumer
+				err error
+			)
+
+			rb := NewRecordBuffer(NewMemory(), false)
+			t.Cleanup(func() { _ = rb.Close() })
+			for _, rec := range []string{"a", "b", "c"} {
+				if err = rb.WriteRecord([]byte(rec)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if g, err = NewConsumerGroup(rb, "workers", 50*time.Millisecond); err != nil {
+				t.Fatalf("NewConsumerGroup() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = g.Close() })
+
+			if c, err = g.Consumer(); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = c.Close() })
+
+			got := tt.run(t, g, c)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Receive() invalid records, expected <%v> and received <%v>", tt.want, got)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Receive() invalid records, expected <%v> and received <%v>", tt.want, got)
+				}
+			}
+
+			if committed := g.Committed(); committed != tt.wantCommitted {
+				t.Fatalf("Committed() invalid value, expected <%d> and received <%d>", tt.wantCommitted, committed)
+			}
+		})
+	}
+}
+
+func Test_ConsumerGroup_exactly_one_member(t *testing.T) {
+	const records = 200
+
+	var (
+		g   *ConsumerGroup
+		err error
+	)
+
+	b := NewMemory()
+	rb := NewRecordBuffer(b, true)
+	if g, err = NewConsumerGroup(rb, "workers", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = g.Close() })
+
+	var (
+		mux  sync.Mutex
+		seen = make(map[int64]int)
+		wg   sync.WaitGroup
+	)
+
+	for i := 0; i < 4; i++ {
+		var c *Consumer
+		if c, err = g.Consumer(); err != nil {
+			t.Fatal(err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer c.Close()
+			for {
+				m, receiveErr := c.Receive(context.Background())
+				if receiveErr != nil {
+					if !errors.Is(receiveErr, io.EOF) {
+						t.Errorf("Receive() unexpected error: %v", receiveErr)
+					}
+
+					return
+				}
+
+				mux.Lock()
+				seen[m.Offset]++
+				mux.Unlock()
+				_ = m.Ack()
+			}
+		}()
+	}
+
+	for i := 0; i < records; i++ {
+		if err = rb.WriteRecord([]byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Once the Buffer closes and every record is acknowledged, members receive io.EOF.
+	_ = b.Close()
+	wg.Wait()
+
+	if len(seen) != records {
+		t.Fatalf("Receive() invalid record count, expected <%d> and received <%d>", records, len(seen))
+	}
+
+	for offset, count := range seen {
+		if count != 1 {
+			t.Fatalf("Receive() record at offset %d delivered <%d> times", offset, count)
+		}
+	}
+
+	if committed := g.Committed(); committed != records*6 {
+		t.Fatalf("Committed() invalid value, expected <%d> and received <%d>", records*6, committed)
+	}
+}
+
+func Test_ConsumerGroup_restart(t *testing.T) {
+	var (
+		g   *ConsumerGroup
+		c   *Consumer
+		m   *Message
+		err error
+	)
+
+	rb := NewRecordBuffer(NewMemory(), false)
+	t.Cleanup(func() { _ = rb.Close() })
+	for _, rec := range []string{"a", "b"} {
+		if err = rb.WriteRecord([]byte(rec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []string{"a", "b"} {
+		if g, err = NewConsumerGroup(rb, "workers", time.Minute); err != nil {
+			t.Fatal(err)
+		}
+
+		if c, err = g.Consumer(); err != nil {
+			t.Fatal(err)
+		}
+
+		if m, err = c.Receive(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if string(m.Data) != want {
+			t.Fatalf("Receive() invalid value, expected <%q> and received <%q>", want, m.Data)
+		}
+
+		// Only the acknowledged record is skipped by the next group.
+		if err = m.Ack(); err != nil {
+			t.Fatal(err)
+		}
+
+		_ = c.Close()
+		_ = g.Close()
+	}
+
+	if _, err = NewConsumerGroup(rb, "workers", 0); !errors.Is(err, ErrInvalidInterval) {
+		t.Fatalf("NewConsumerGroup() invalid error, expected <%v> and received <%v>", ErrInvalidInterval, err)
+	}
+}
+
+func Test_ConsumerGroup_CloseAndWait(t *testing.T) {
+	var (
+		g   *ConsumerGroup
+		c   *Consumer
+		err error
+	)
+
+	b := NewMemory()
+	rb := NewRecordBuffer(b, false)
+	if err = rb.WriteRecord([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, err = NewConsumerGroup(rb, "workers", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if c, err = g.Consumer(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// An open group with no open members must not hold CloseAndWait.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = b.CloseAndWait(ctx); err != nil {
+		t.Fatalf("CloseAndWait() unexpected error: %v", err)
+	}
+
+	if ctx.Err() != nil {
+		t.Fatalf("CloseAndWait() invalid wait, expected readers released and received <%v>", ctx.Err())
+	}
+
+	if err = g.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package streambuf
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ContextReader is implemented by the readers returned from Reader and
+// StreamingReader. It bounds individual reads without closing the reader, so
+// the reader keeps its offset and remains usable after a timeout.
+type ContextReader interface {
+	io.ReadSeekCloser
+
+	// ReadContext behaves like Read but returns ctx.Err() once ctx is done.
+	ReadContext(ctx context.Context, in []byte) (n int, err error)
+	// SetReadDeadline bounds pending and future reads, which return
+	// os.ErrDeadlineExceeded once t passes. A zero t clears the deadline.
+	SetReadDeadline(t time.Time) (err error)
+}
@@ -0,0 +1,128 @@
+package streambuf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const cursorsExt = ".cursors"
+
+// newCursors constructs the committed offsets of named readers. An empty path
+// keeps them in memory; otherwise they are persisted as JSON at path.
+// The file is loaded on first use.
+func newCursors(path string, perm os.FileMode) (out *cursors) {
+	var c cursors
+	c.path = path
+	c.perm = perm
+	return &c
+}
+
+// cursors stores the committed offset of each named reader.
+type cursors struct {
+	mux sync.Mutex
+
+	path string
+	perm os.FileMode
+
+	// offsets is nil until loaded.
+	offsets map[string]int64
+}
+
+// get returns the committed offset for name, or 0 if none was committed.
+func (c *cursors) get(name string) (offset int64, err error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if err = c.load(); err != nil {
+		return 0, err
+	}
+
+	return c.offsets[name], nil
+}
+
+// commit records offset for name and persists every offset.
+// Committing an unchanged offset does not write the file.
+func (c *cursors) commit(name string, offset int64) (err error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if err = c.load(); err != nil {
+		return err
+	}
+
+	if current, ok := c.offsets[name]; ok && current == offset {
+		return nil
+	}
+
+	c.offsets[name] = offset
+	return c.save()
+}
+
+// load reads the persisted offsets once. The caller must hold the lock.
+func (c *cursors) load() (err error) {
+	var bs []byte
+	if c.offsets != nil {
+		return nil
+	}
+
+	c.offsets = make(map[string]int64)
+	if c.path == "" {
+		return nil
+	}
+
+	switch bs, err = os.ReadFile(c.path); {
+	case errors.Is(err, os.ErrNotExist):
+		return nil
+	case err != nil:
+		c.offsets = nil
+		return fmt.Errorf("read cursors: %w", err)
+	}
+
+	if err = json.Unmarshal(bs, &c.offsets); err != nil {
+		c.offsets = nil
+		return fmt.Errorf("decode cursors: %w", err)
+	}
+
+	return nil
+}
+
+// save atomically replaces the cursor file so a crash never leaves it torn.
+// The caller must hold the lock.
+func (c *cursors) save() (err error) {
+	var (
+		bs []byte
+		f  *os.File
+	)
+
+	if c.path == "" {
+		return nil
+	}
+
+	if bs, err = json.Marshal(c.offsets); err != nil {
+		return fmt.Errorf("encode cursors: %w", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if f, err = os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, c.perm); err != nil {
+		return fmt.Errorf("create cursors: %w", err)
+	}
+
+	if _, err = f.Write(bs); err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("write cursors: %w", err)
+	}
+
+	if err = os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("replace cursors: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package streambuf
+
+import (
+	"sync"
+	"time"
+)
+
+// newDeadline constructs a deadline with no expiration set.
+func newDeadline() (out *deadline) {
+	var d deadline
+	d.c = make(chan struct{})
+	return &d
+}
+
+// deadline exposes a channel that is closed once a configurable time passes.
+// Setting a new time affects pending waiters, matching net.Conn semantics.
+type deadline struct {
+	mux sync.Mutex
+
+	timer *time.Timer
+	c     chan struct{}
+}
+
+// Set replaces the expiration time. A zero t clears the deadline.
+func (d *deadline) Set(t time.Time) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.timer != nil && !d.timer.Stop() {
+		// The timer already fired, so wait on the channel it closed.
+		<-d.c
+	}
+
+	d.timer = nil
+	if isClosedChan(d.c) {
+		d.c = make(chan struct{})
+	}
+
+	if t.IsZero() {
+		return
+	}
+
+	until := time.Until(t)
+	if until <= 0 {
+		close(d.c)
+		return
+	}
+
+	c := d.c
+	d.timer = time.AfterFunc(until, func() {
+		close(c)
+	})
+}
+
+// Wait returns a channel that is closed when the deadline passes.
+func (d *deadline) Wait() (out <-chan struct{}) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.c
+}
+
+// isClosedChan reports whether c has been closed.
+func isClosedChan(c <-chan struct{}) (closed bool) {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,30 @@
+package streambuf
+
+import "time"
+
+// delivery tracks one record of a ConsumerGroup until it is acknowledged.
+type delivery struct {
+	offset int64
+	end    int64
+	data   []byte
+
+	// attempts counts deliveries; 0 means the record is awaiting its first consumer.
+	attempts int
+	// deadline is when an unacknowledged delivery becomes visible again.
+	deadline time.Time
+	owner    *Consumer
+	acked    bool
+}
+
+// redeliverable reports whether the record was delivered and is due again.
+func (d *delivery) redeliverable(now time.Time) (ok bool) {
+	return !d.acked && d.attempts > 0 && !d.deadline.After(now)
+}
+
+// deliver hands the record to c until now plus visibility and returns its Message.
+func (d *delivery) deliver(g *ConsumerGroup, c *Consumer, now time.Time) (m *Message) {
+	d.attempts++
+	d.deadline = now.Add(g.visibility)
+	d.owner = c
+	return newMessage(g, d)
+}
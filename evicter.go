@@ -0,0 +1,9 @@
+package streambuf
+
+// evicter is implemented by backends that discard their oldest bytes on their
+// own as they are written, such as memory rings, so the Buffer can raise its
+// low-water mark to match.
+type evicter interface {
+	// evicted returns the absolute offset of the oldest retained byte.
+	evicted() (offset int64)
+}
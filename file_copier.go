@@ -0,0 +1,17 @@
+package streambuf
+
+import "os"
+
+// fileCopier is implemented by file backends that can append bytes from a
+// regular file inside the kernel.
+type fileCopier interface {
+	// copyFrom appends up to n bytes read from src at its current offset.
+	// It returns 0 once src is exhausted.
+	copyFrom(src *os.File, n int64) (written int64, err error)
+}
+
+// isRegularFile reports whether f is a regular file, whose reads never block.
+func isRegularFile(f *os.File) (ok bool) {
+	info, err := f.Stat()
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,67 @@
+package streambuf
+
+import (
+	"context"
+	"io"
+)
+
+// NewFollowStream constructs a read-only Stream over a file that another
+// process appends to, like tail -F with independent cursors. Growth wakes
+// StreamingReader readers; truncation and rotation of the path are reported
+// to readers as ErrTruncated and ErrRotated.
+// WithPollInterval sets how often the file is checked.
+func NewFollowStream(filepath string, opts ...Option) (out *FollowStream, err error) {
+	var (
+		s FollowStream
+		r *readableFollow
+	)
+
+	c := newConfig(opts)
+	if err = c.validateFor(0); err != nil {
+		return nil, err
+	}
+
+	if r, err = newReadableFollow(filepath); err != nil {
+		return nil, err
+	}
+
+	s.stream = newStreamWithReadable(r, c)
+	if s.f, err = newFollower(s.stream, r); err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// FollowStream is a read-only Stream that follows a file written by another process.
+type FollowStream struct {
+	*stream
+
+	f *follower
+}
+
+// StreamingReader returns a new io.ReadSeekCloser that waits for the followed
+// file to grow when the current end is reached.
+// It returns ErrIsClosed if the stream is closed.
+func (s *FollowStream) StreamingReader() (r io.ReadSeekCloser, err error) {
+	var rd *reader
+	if rd, err = s.openReader(true); err != nil {
+		return nil, err
+	}
+
+	return rd, nil
+}
+
+// Close stops following the file, closes the stream, and signals waiting readers.
+// It does not wait for readers to call Close.
+func (s *FollowStream) Close() (err error) {
+	return s.CloseAndWait(expiredContext)
+}
+
+// CloseAndWait stops following the file, closes the stream, and waits for
+// readers to close until ctx is canceled.
+func (s *FollowStream) CloseAndWait(ctx context.Context) (err error) {
+	_ = s.f.Close()
+	return s.stream.CloseAndWait(ctx)
+}
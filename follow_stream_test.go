@@ -0,0 +1,191 @@
+package streambuf
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func Test_FollowStream_StreamingReader(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		pollInterval time.Duration
+		linuxOnly    bool
+
+		change func(t *testing.T, path string) (err error)
+
+		wantErr  error
+		wantRead string
+	}
+
+	appendFile := func(t *testing.T, path string) (err error) {
+		var f *os.File
+		if f, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0); err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = f.WriteString(" world")
+		return err
+	}
+
+	tests := []testcase{
+		{
+			name:         "growth detected by polling",
+			pollInterval: 10 * time.Millisecond,
+			change:       appendFile,
+			wantRead:     " world",
+		},
+		{
+			name:         "growth detected by inotify",
+			pollInterval: time.Hour,
+			linuxOnly:    true,
+			change:       appendFile,
+			wantRead:     " world",
+		},
+		{
+			name:         "truncation",
+			pollInterval: 10 * time.Millisecond,
+			change: func(t *testing.T, path string) (err error) {
+				return os.WriteFile(path, []byte("hi"), 0644)
+			},
+			wantErr:  ErrTruncated,
+			wantRead: "hi",
+		},
+		{
+			name:         "rotation",
+			pollInterval: 10 * time.Millisecond,
+			change: func(t *testing.T, path string) (err error) {
+				if err = os.Rename(path, path+".1"); err != nil {
+					return err
+				}
+
+				return os.WriteFile(path, []byte("rotated"), 0644)
+			},
+			wantErr:  ErrRotated,
+			wantRead: "rotated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				s   *FollowStream
+				r   io.ReadSeekCloser
+				err error
+			)
+
+			if tt.linuxOnly && runtime.GOOS != "linux" {
+				t.Skip("requires inotify")
+			}
+
+			path := filepath.Join(t.TempDir(), "follow.log")
+			if err = os.WriteFile(path, []byte("hello"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if s, err = NewFollowStream(path, WithPollInterval(tt.pollInterval)); err != nil {
+				t.Fatalf("NewFollowStream() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = s.Close() })
+
+			if r, err = s.StreamingReader(); err != nil {
+				t.Fatalf("StreamingReader() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = r.Close() })
+
+			if got := readFollow(t, r, 5); got != "hello" {
+				t.Fatalf("Read() invalid value, expected <%q> and received <%q>", "hello", got)
+			}
+
+			if err = tt.change(t, path); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantErr != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+
+				_, err = r.(ContextReader).ReadContext(ctx, make([]byte, 8))
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Read() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+				}
+			}
+
+			if got := readFollow(t, r, len(tt.wantRead)); got != tt.wantRead {
+				t.Fatalf("Read() invalid value, expected <%q> and received <%q>", tt.wantRead, got)
+			}
+		})
+	}
+}
+
+func Test_FollowStream_Close(t *testing.T) {
+	var (
+		s   *FollowStream
+		r   io.ReadSeekCloser
+		err error
+	)
+
+	path := filepath.Join(t.TempDir(), "follow.log")
+	if err = os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err = NewFollowStream(path); err != nil {
+		t.Fatalf("NewFollowStream() unexpected error: %v", err)
+	}
+
+	if r, err = s.StreamingReader(); err != nil {
+		t.Fatalf("StreamingReader() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+
+	done := make(chan error, 1)
+	go func() {
+		_, readErr := r.Read(make([]byte, 8))
+		done <- readErr
+	}()
+
+	if err = s.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	select {
+	case err = <-done:
+		if !errors.Is(err, io.EOF) {
+			t.Fatalf("Read() invalid error, expected <%v> and received <%v>", io.EOF, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Read() was not unblocked by Close()")
+	}
+
+	if err = s.Close(); !errors.Is(err, ErrIsClosed) {
+		t.Fatalf("Close() invalid error, expected <%v> and received <%v>", ErrIsClosed, err)
+	}
+}
+
+// readFollow reads exactly n bytes from r, failing the test after five seconds.
+func readFollow(t *testing.T, r io.Reader, n int) (out string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	bs := make([]byte, n)
+	for read := 0; read < n; {
+		count, err := r.(ContextReader).ReadContext(ctx, bs[read:])
+		if err != nil {
+			t.Fatalf("Read() unexpected error: %v", err)
+		}
+
+		read += count
+	}
+
+	return string(bs)
+}
@@ -0,0 +1,109 @@
+package streambuf
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// newFollower starts watching r for growth, truncation, and rotation, waking
+// the readers of s. Polling always runs; inotify is used in addition where available.
+func newFollower(s *stream, r *readableFollow) (out *follower, err error) {
+	var (
+		f    follower
+		info os.FileInfo
+	)
+
+	f.s = s
+	f.r = r
+	if info, _, err = r.stat(); err != nil {
+		return nil, err
+	}
+
+	f.size = info.Size()
+	f.poll = newPeriodic("follow", s.c.pollInterval, s.c.logger, f.check)
+	if f.notifier, err = newNotifier(r.path, f.notify); err != nil {
+		s.c.logger.Warn("streambuf: file notifications unavailable, polling only", "path", r.path, "error", err)
+	}
+
+	return &f, nil
+}
+
+// follower detects changes to a followed file made by other processes.
+type follower struct {
+	mux sync.Mutex
+
+	s *stream
+	r *readableFollow
+
+	// size is the last observed size of the open file.
+	size int64
+
+	poll     *periodic
+	notifier *notifier
+}
+
+// check compares the followed file with its last observed state and wakes or
+// rewinds readers accordingly.
+func (f *follower) check(now time.Time) (err error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	var open, atPath os.FileInfo
+	if open, atPath, err = f.r.stat(); err != nil {
+		return err
+	}
+
+	switch size := open.Size(); {
+	case atPath != nil && !os.SameFile(open, atPath):
+		return f.rotate()
+	case size < f.size:
+		f.size = size
+		return f.s.rewind(ErrTruncated)
+	case size > f.size:
+		f.size = size
+		return f.s.waiter.Refresh()
+	default:
+		return nil
+	}
+}
+
+// rotate reopens the followed path and rewinds readers to the new file.
+func (f *follower) rotate() (err error) {
+	var (
+		info      os.FileInfo
+		rewindErr error
+	)
+
+	if err = f.r.reopen(func() { rewindErr = f.s.rewind(ErrRotated) }); err != nil {
+		return err
+	}
+
+	if rewindErr != nil {
+		return rewindErr
+	}
+
+	if info, _, err = f.r.stat(); err != nil {
+		return err
+	}
+
+	f.size = info.Size()
+	return nil
+}
+
+// notify runs a check when the file system reports a change.
+func (f *follower) notify() {
+	if err := f.check(time.Now()); err != nil && !errors.Is(err, ErrIsClosed) {
+		f.s.c.logger.Warn("streambuf: follow check failed", "path", f.r.path, "error", err)
+	}
+}
+
+// Close stops polling and file notifications.
+func (f *follower) Close() (err error) {
+	if f.notifier != nil {
+		_ = f.notifier.Close()
+	}
+
+	return f.poll.Close()
+}
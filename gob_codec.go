@@ -0,0 +1,28 @@
+package streambuf
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+var _ Codec[struct{}] = GobCodec[struct{}]{}
+
+// GobCodec encodes values with encoding/gob.
+// Every record carries its own type information so readers may start at any record.
+type GobCodec[T any] struct{}
+
+// Encode returns the gob encoding of v.
+func (GobCodec[T]) Encode(v T) (bs []byte, err error) {
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode parses the gob encoding in bs.
+func (GobCodec[T]) Decode(bs []byte) (v T, err error) {
+	err = gob.NewDecoder(bytes.NewReader(bs)).Decode(&v)
+	return v, err
+}
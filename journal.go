@@ -0,0 +1,176 @@
+package streambuf
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+)
+
+const (
+	journalExt = ".idx"
+	// checkpointExt names the sidecar holding the length confirmed by a
+	// compacted journal and the persisted low-water mark.
+	checkpointExt = ".ckpt"
+	// journalCompactEntries is the number of journal entries after which a
+	// sync compacts the journal into a checkpoint.
+	journalCompactEntries = 1 << 12
+)
+
+var journalTable = crc32.MakeTable(crc32.Castagnoli)
+
+// newJournal opens the sidecar journal for the data file at filepath.
+func newJournal(filepath string, perm os.FileMode) (out *journal, err error) {
+	var j journal
+	j.path = filepath + journalExt
+	j.checkpointPath = filepath + checkpointExt
+	j.perm = perm
+	if j.f, err = os.OpenFile(j.path, os.O_RDWR|os.O_APPEND|os.O_CREATE, perm); err != nil {
+		return nil, fmt.Errorf("open journal file: %w", err)
+	}
+
+	return &j, nil
+}
+
+// journal is a sidecar file holding one checksummed entry per write to a data
+// file. Synced entries are periodically compacted into a checkpoint, so the
+// journal and the work done by recovery stay bounded.
+type journal struct {
+	path           string
+	checkpointPath string
+	f              *os.File
+	perm           os.FileMode
+
+	// count is the number of entries written since the last compaction.
+	count int
+	// low is the low-water mark carried into every checkpoint.
+	low int64
+}
+
+// entries returns every complete entry in the journal.
+// A partially written trailing entry is ignored.
+func (j *journal) entries() (out []journalEntry, err error) {
+	var bs []byte
+	if bs, err = os.ReadFile(j.path); err != nil {
+		return nil, fmt.Errorf("read journal file: %w", err)
+	}
+
+	for len(bs) >= journalEntrySize {
+		out = append(out, decodeJournalEntry(bs[:journalEntrySize]))
+		bs = bs[journalEntrySize:]
+	}
+
+	j.count = len(out)
+	return out, nil
+}
+
+// reset rewrites the journal so it contains exactly entries.
+func (j *journal) reset(entries []journalEntry) (err error) {
+	if err = j.f.Truncate(0); err != nil {
+		return fmt.Errorf("truncate journal file: %w", err)
+	}
+
+	j.count = 0
+	for _, e := range entries {
+		if err = j.append(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// append writes a single entry to the end of the journal.
+func (j *journal) append(e journalEntry) (err error) {
+	bs := e.encode()
+	if _, err = j.f.Write(bs[:]); err != nil {
+		return fmt.Errorf("write journal file: %w", err)
+	}
+
+	j.count++
+	return nil
+}
+
+// checkpointed returns the last checkpoint, or a zero checkpoint when there is
+// no valid one.
+func (j *journal) checkpointed() (cp journalCheckpoint, err error) {
+	var bs []byte
+	switch bs, err = os.ReadFile(j.checkpointPath); {
+	case errors.Is(err, os.ErrNotExist):
+		return cp, nil
+	case err != nil:
+		return cp, fmt.Errorf("read journal checkpoint: %w", err)
+	}
+
+	cp, _ = decodeJournalCheckpoint(bs)
+	return cp, nil
+}
+
+// truncate checkpoints length along with the low-water mark low, so recovery
+// does not checksum bytes that Truncate is about to reclaim. The bytes before
+// length must already be on stable storage.
+func (j *journal) truncate(length, low int64) (err error) {
+	j.low = low
+	return j.compact(length)
+}
+
+// compact records length as checkpointed and empties the journal. The bytes
+// before length must already be on stable storage. The checkpoint is replaced
+// atomically, and entries left behind by a crash before the journal is
+// emptied are skipped by recovery.
+func (j *journal) compact(length int64) (err error) {
+	var (
+		cp journalCheckpoint
+		f  *os.File
+	)
+
+	cp.length = length
+	cp.low = j.low
+	bs := cp.encode()
+
+	tmp := j.checkpointPath + ".tmp"
+	if f, err = os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, j.perm); err != nil {
+		return fmt.Errorf("create journal checkpoint: %w", err)
+	}
+
+	if _, err = f.Write(bs[:]); err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return fmt.Errorf("write journal checkpoint: %w", err)
+	}
+
+	if err = os.Rename(tmp, j.checkpointPath); err != nil {
+		return fmt.Errorf("replace journal checkpoint: %w", err)
+	}
+
+	return j.reset(nil)
+}
+
+// sync flushes the journal to stable storage.
+func (j *journal) sync() (err error) {
+	if err = j.f.Sync(); err != nil {
+		return fmt.Errorf("sync journal file: %w", err)
+	}
+
+	return nil
+}
+
+// close closes the journal file handle.
+func (j *journal) close() (err error) {
+	if err = j.f.Close(); err != nil {
+		return fmt.Errorf("close journal file: %w", err)
+	}
+
+	return nil
+}
+
+// checksum returns the journal checksum of bs.
+func checksum(bs []byte) (crc uint32) {
+	return crc32.Checksum(bs, journalTable)
+}
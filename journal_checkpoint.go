@@ -0,0 +1,37 @@
+package streambuf
+
+import "encoding/binary"
+
+const journalCheckpointSize = 20
+
+// journalCheckpoint records the length confirmed by a compacted journal and
+// the low-water mark before which Truncate may have reclaimed the data file.
+type journalCheckpoint struct {
+	length int64
+	low    int64
+}
+
+// encode returns the fixed-size big-endian form of the checkpoint, followed by
+// its own checksum.
+func (c journalCheckpoint) encode() (out [journalCheckpointSize]byte) {
+	binary.BigEndian.PutUint64(out[:8], uint64(c.length))
+	binary.BigEndian.PutUint64(out[8:16], uint64(c.low))
+	binary.BigEndian.PutUint32(out[16:], checksum(out[:16]))
+	return out
+}
+
+// decodeJournalCheckpoint parses the fixed-size big-endian form of a
+// checkpoint. ok is false when in is not a consistent checkpoint.
+func decodeJournalCheckpoint(in []byte) (c journalCheckpoint, ok bool) {
+	if len(in) != journalCheckpointSize || binary.BigEndian.Uint32(in[16:]) != checksum(in[:16]) {
+		return c, false
+	}
+
+	c.length = int64(binary.BigEndian.Uint64(in[:8]))
+	c.low = int64(binary.BigEndian.Uint64(in[8:16]))
+	if c.low < 0 || c.low > c.length {
+		return journalCheckpoint{}, false
+	}
+
+	return c, true
+}
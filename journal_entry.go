@@ -0,0 +1,25 @@
+package streambuf
+
+import "encoding/binary"
+
+const journalEntrySize = 12
+
+// journalEntry records the end offset and checksum of a single write.
+type journalEntry struct {
+	end int64
+	crc uint32
+}
+
+// encode returns the fixed-size big-endian form of the entry.
+func (e journalEntry) encode() (out [journalEntrySize]byte) {
+	binary.BigEndian.PutUint64(out[:8], uint64(e.end))
+	binary.BigEndian.PutUint32(out[8:], e.crc)
+	return out
+}
+
+// decodeJournalEntry parses the fixed-size big-endian form of an entry.
+func decodeJournalEntry(in []byte) (e journalEntry) {
+	e.end = int64(binary.BigEndian.Uint64(in[:8]))
+	e.crc = binary.BigEndian.Uint32(in[8:])
+	return e
+}
@@ -0,0 +1,19 @@
+package streambuf
+
+import "encoding/json"
+
+var _ Codec[struct{}] = JSONCodec[struct{}]{}
+
+// JSONCodec encodes values with encoding/json.
+type JSONCodec[T any] struct{}
+
+// Encode returns the JSON encoding of v.
+func (JSONCodec[T]) Encode(v T) (bs []byte, err error) {
+	return json.Marshal(v)
+}
+
+// Decode parses the JSON encoding in bs.
+func (JSONCodec[T]) Decode(bs []byte) (v T, err error) {
+	err = json.Unmarshal(bs, &v)
+	return v, err
+}
@@ -16,6 +16,8 @@ type memory struct {
 	mux sync.RWMutex
 
 	bs []byte
+	// offset is the absolute stream offset of bs[0].
+	offset int64
 }
 
 // write applies fn while holding the write lock and stores the returned slice.
@@ -25,9 +27,38 @@ func (m *memory) write(fn func(in []byte) (out []byte)) {
 	m.bs = fn(m.bs)
 }
 
-// read invokes fn while holding the read lock.
-func (m *memory) read(fn func(in []byte)) {
+// read invokes fn with the retained bytes and their absolute offset while
+// holding the read lock.
+func (m *memory) read(fn func(in []byte, offset int64)) {
 	m.mux.RLock()
 	defer m.mux.RUnlock()
-	fn(m.bs)
+	fn(m.bs, m.offset)
+}
+
+// evict discards the oldest bytes so at most capacity bytes are retained.
+// The offset advances by the discarded count so absolute offsets stay monotonic.
+func (m *memory) evict(capacity int64) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	excess := int64(len(m.bs)) - capacity
+	if excess <= 0 {
+		return
+	}
+
+	m.bs = m.bs[excess:]
+	m.offset += excess
+}
+
+// truncate discards the retained bytes before the absolute offset.
+// Offsets at or below the current offset are a no-op.
+func (m *memory) truncate(offset int64) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	excess := min(offset-m.offset, int64(len(m.bs)))
+	if excess <= 0 {
+		return
+	}
+
+	m.bs = m.bs[excess:]
+	m.offset += excess
 }
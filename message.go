@@ -0,0 +1,44 @@
+package streambuf
+
+// newMessage constructs the Message for the current delivery of d.
+func newMessage(g *ConsumerGroup, d *delivery) (out *Message) {
+	var m Message
+	m.Offset = d.offset
+	m.Data = d.data
+	m.Attempt = d.attempts
+	m.attempt = d.attempts
+	m.g = g
+	m.d = d
+	return &m
+}
+
+// Message is a record delivered to one member of a ConsumerGroup.
+type Message struct {
+	// Offset is the absolute offset of the record.
+	Offset int64
+	// Data is the record payload.
+	Data []byte
+	// Attempt is 1 on first delivery and increases with each redelivery.
+	Attempt int
+
+	g *ConsumerGroup
+	d *delivery
+	// attempt is the delivery this Message was handed out for.
+	attempt int
+}
+
+// Ack marks the record as processed so it is never redelivered. The group's
+// committed offset advances past every record acknowledged in order.
+// Acknowledging a record twice is a no-op. Once this delivery expired and the
+// record was redelivered, Ack returns ErrStaleDelivery and only the member
+// holding the latest delivery may acknowledge it.
+func (m *Message) Ack() (err error) {
+	return m.g.ack(m.d, m.attempt)
+}
+
+// Nack makes the record immediately available for redelivery. It is a no-op
+// once the record was acknowledged, and returns ErrStaleDelivery once the
+// record was redelivered after this delivery expired.
+func (m *Message) Nack() (err error) {
+	return m.g.nack(m.d, m.attempt)
+}
@@ -0,0 +1,12 @@
+package streambuf
+
+// Metrics receives instrumentation events from a Buffer or Stream.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// Written is called after each successful Write with the number of bytes appended.
+	Written(n int)
+	// ReaderOpened is called when a reader is created.
+	ReaderOpened()
+	// ReaderClosed is called when a reader is closed.
+	ReaderClosed()
+}
@@ -0,0 +1,114 @@
+package streambuf
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+var (
+	_ ContextReader = &NamedReader{}
+	_ Peeker        = &NamedReader{}
+	_ io.WriterTo   = &NamedReader{}
+)
+
+// newNamedReader constructs a NamedReader positioned at offset.
+// A positive autoCommit interval commits the offset periodically.
+func newNamedReader(r *reader, name string, c *cursors, autoCommit time.Duration) (out *NamedReader) {
+	var nr NamedReader
+	nr.r = r
+	nr.name = name
+	nr.cursors = c
+	r.s.readers.setName(r, name)
+	if autoCommit > 0 {
+		nr.auto = newPeriodic("cursor", autoCommit, r.s.c.logger, func(now time.Time) (err error) {
+			return nr.Commit()
+		})
+	}
+
+	return &nr
+}
+
+// NamedReader is a StreamingReader whose offset can be committed under a
+// name, so a restarted consumer resumes where it last committed.
+// Committing after processing gives at-least-once delivery.
+type NamedReader struct {
+	r    *reader
+	name string
+
+	cursors *cursors
+	auto    *periodic
+}
+
+// Name returns the cursor name.
+func (nr *NamedReader) Name() (name string) {
+	return nr.name
+}
+
+// Offset returns the absolute offset of the next byte to be read.
+func (nr *NamedReader) Offset() (offset int64) {
+	return nr.r.position.Load()
+}
+
+// Read reads like a StreamingReader, waiting for future writes at the current end.
+func (nr *NamedReader) Read(in []byte) (n int, err error) {
+	return nr.ReadContext(context.Background(), in)
+}
+
+// ReadContext behaves like Read but returns ctx.Err() once ctx is done.
+func (nr *NamedReader) ReadContext(ctx context.Context, in []byte) (n int, err error) {
+	return nr.r.ReadContext(ctx, in)
+}
+
+// Peek returns up to n of the next unread bytes without advancing the reader.
+// The returned slice must not be modified and is only valid until the reader advances.
+func (nr *NamedReader) Peek(n int) (out []byte, err error) {
+	return nr.r.Peek(n)
+}
+
+// Next behaves like Peek but advances the reader past the returned bytes.
+func (nr *NamedReader) Next(n int) (out []byte, err error) {
+	return nr.r.Next(n)
+}
+
+// WriteTo writes unread bytes to w until the Buffer closes.
+func (nr *NamedReader) WriteTo(w io.Writer) (n int64, err error) {
+	return nr.r.WriteTo(w)
+}
+
+// SetReadDeadline sets the deadline of the wrapped reader.
+func (nr *NamedReader) SetReadDeadline(t time.Time) (err error) {
+	return nr.r.SetReadDeadline(t)
+}
+
+// Seek moves the reader like the reader returned by StreamingReader.
+// The new position is not committed until Commit is called.
+func (nr *NamedReader) Seek(offset int64, whence int) (pos int64, err error) {
+	return nr.r.Seek(offset, whence)
+}
+
+// Commit checkpoints the current offset under the reader's name.
+// It returns ErrIsClosed after Close.
+func (nr *NamedReader) Commit() (err error) {
+	if isClosedChan(nr.r.closer.Wait()) {
+		return ErrIsClosed
+	}
+
+	return nr.cursors.commit(nr.name, nr.Offset())
+}
+
+// Close closes the reader. With WithAutoCommit, the final offset is committed
+// first and a commit error is returned after the reader is closed.
+func (nr *NamedReader) Close() (err error) {
+	var commitErr error
+	if nr.auto != nil {
+		_ = nr.auto.Close()
+		commitErr = nr.Commit()
+	}
+
+	if err = nr.r.Close(); err != nil {
+		return err
+	}
+
+	return commitErr
+}
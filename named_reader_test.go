@@ -0,0 +1,205 @@
+package streambuf
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_Buffer_NamedReader(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		// init returns a constructor that reopens the same Buffer.
+		init func(t *testing.T) (open func() (b *Buffer, err error))
+
+		read      int
+		commit    bool
+		reopen    bool
+		truncate  int64
+		readName  string
+		wantStart int64
+	}
+
+	fileBuffer := func(t *testing.T) (open func() (b *Buffer, err error)) {
+		path := t.TempDir() + "/buffer.tmp"
+		return func() (b *Buffer, err error) {
+			return New(path)
+		}
+	}
+
+	memoryBuffer := func(t *testing.T) (open func() (b *Buffer, err error)) {
+		b := NewMemory()
+		return func() (out *Buffer, err error) {
+			return b, nil
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:      "file commit survives reopen",
+			init:      fileBuffer,
+			read:      5,
+			commit:    true,
+			reopen:    true,
+			readName:  "parser",
+			wantStart: 5,
+		},
+		{
+			name:      "file uncommitted offset is not restored",
+			init:      fileBuffer,
+			read:      5,
+			reopen:    true,
+			readName:  "parser",
+			wantStart: 0,
+		},
+		{
+			name:      "file other name starts at zero",
+			init:      fileBuffer,
+			read:      5,
+			commit:    true,
+			reopen:    true,
+			readName:  "other",
+			wantStart: 0,
+		},
+		{
+			name:      "memory commit shared by later readers",
+			init:      memoryBuffer,
+			read:      3,
+			commit:    true,
+			readName:  "parser",
+			wantStart: 3,
+		},
+		{
+			name:      "evicted commit starts at low-water mark",
+			init:      memoryBuffer,
+			read:      3,
+			commit:    true,
+			truncate:  6,
+			readName:  "parser",
+			wantStart: 6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				b   *Buffer
+				nr  *NamedReader
+				err error
+			)
+
+			open := tt.init(t)
+			if b, err = open(); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = b.Write([]byte("hello world")); err != nil {
+				t.Fatal(err)
+			}
+
+			if nr, err = b.NamedReader("parser"); err != nil {
+				t.Fatalf("NamedReader() unexpected error: %v", err)
+			}
+
+			if _, err = io.ReadFull(nr, make([]byte, tt.read)); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.commit {
+				if err = nr.Commit(); err != nil {
+					t.Fatalf("Commit() unexpected error: %v", err)
+				}
+			}
+
+			if err = nr.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.truncate > 0 {
+				if err = b.Truncate(tt.truncate); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tt.reopen {
+				if err = b.Close(); err != nil {
+					t.Fatal(err)
+				}
+
+				if b, err = open(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Cleanup(func() { _ = b.Close() })
+
+			if nr, err = b.NamedReader(tt.readName); err != nil {
+				t.Fatalf("NamedReader() unexpected error: %v", err)
+			}
+			t.Cleanup(func() { _ = nr.Close() })
+
+			if got := nr.Offset(); got != tt.wantStart {
+				t.Fatalf("Offset() invalid value, expected <%d> and received <%d>", tt.wantStart, got)
+			}
+		})
+	}
+}
+
+func Test_NamedReader_WithAutoCommit(t *testing.T) {
+	var (
+		b   *Buffer
+		nr  *NamedReader
+		err error
+	)
+
+	path := t.TempDir() + "/buffer.tmp"
+	if b, err = New(path, WithAutoCommit(10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = b.Close() })
+
+	if _, err = b.Write([]byte("hello world")); err != nil {
+		t.Fatal(err)
+	}
+
+	if nr, err = b.NamedReader("parser"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = io.ReadFull(nr, make([]byte, 4)); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if bs, _ := os.ReadFile(path + cursorsExt); string(bs) == `{"parser":4}` {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("WithAutoCommit() did not commit the offset")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err = io.ReadFull(nr, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Close commits the final offset.
+	if err = nr.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	var offset int64
+	if offset, err = newCursors(path+cursorsExt, 0644).get("parser"); err != nil || offset != 6 {
+		t.Fatalf("get() invalid value, expected <6> and received <%d> (%v)", offset, err)
+	}
+
+	if err = nr.Commit(); !errors.Is(err, ErrIsClosed) {
+		t.Fatalf("Commit() invalid error, expected <%v> and received <%v>", ErrIsClosed, err)
+	}
+}
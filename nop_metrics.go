@@ -0,0 +1,15 @@
+package streambuf
+
+var _ Metrics = nopMetrics{}
+
+// nopMetrics discards every instrumentation event.
+type nopMetrics struct{}
+
+// Written implements Metrics.
+func (nopMetrics) Written(n int) {}
+
+// ReaderOpened implements Metrics.
+func (nopMetrics) ReaderOpened() {}
+
+// ReaderClosed implements Metrics.
+func (nopMetrics) ReaderClosed() {}
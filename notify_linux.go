@@ -0,0 +1,96 @@
+//go:build linux
+
+package streambuf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// notifyMask selects directory events that can change the followed file.
+const notifyMask = syscall.IN_MODIFY | syscall.IN_ATTRIB | syscall.IN_CLOSE_WRITE |
+	syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// newNotifier watches the directory of path with inotify and calls fn for
+// every event naming the file. The directory is watched so that rotations,
+// which replace the path, are observed.
+func newNotifier(path string, fn func()) (out *notifier, err error) {
+	var (
+		n  notifier
+		fd int
+	)
+
+	if fd, err = syscall.InotifyInit1(syscall.IN_NONBLOCK | syscall.IN_CLOEXEC); err != nil {
+		return nil, fmt.Errorf("init inotify: %w", err)
+	}
+
+	if _, err = syscall.InotifyAddWatch(fd, filepath.Dir(path), notifyMask); err != nil {
+		_ = syscall.Close(fd)
+		return nil, fmt.Errorf("watch %q: %w", filepath.Dir(path), err)
+	}
+
+	// A non-blocking descriptor is registered with the runtime poller, so
+	// Close unblocks a pending Read.
+	n.f = os.NewFile(uintptr(fd), "inotify")
+	n.name = filepath.Base(path)
+	go n.run(fn)
+	return &n, nil
+}
+
+// notifier delivers inotify events for a single file.
+type notifier struct {
+	f    *os.File
+	name string
+}
+
+// run reads events until the notifier is closed.
+func (n *notifier) run(fn func()) {
+	var (
+		count int
+		err   error
+	)
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		if count, err = n.f.Read(buf); err != nil {
+			return
+		}
+
+		if n.matches(buf[:count]) {
+			fn()
+		}
+	}
+}
+
+// matches reports whether any event in bs names the followed file.
+func (n *notifier) matches(bs []byte) (ok bool) {
+	for len(bs) >= syscall.SizeofInotifyEvent {
+		length := int(binary.NativeEndian.Uint32(bs[12:16]))
+		end := syscall.SizeofInotifyEvent + length
+		if end > len(bs) {
+			return false
+		}
+
+		name := bytes.TrimRight(bs[syscall.SizeofInotifyEvent:end], "\x00")
+		if string(name) == n.name {
+			return true
+		}
+
+		bs = bs[end:]
+	}
+
+	return false
+}
+
+// Close stops delivering events.
+func (n *notifier) Close() (err error) {
+	if err = n.f.Close(); err != nil {
+		return fmt.Errorf("close inotify: %w", err)
+	}
+
+	return nil
+}
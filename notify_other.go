@@ -0,0 +1,17 @@
+//go:build !linux
+
+package streambuf
+
+// newNotifier returns a notifier that never fires; follow Streams rely on
+// polling on platforms without inotify.
+func newNotifier(path string, fn func()) (out *notifier, err error) {
+	return &notifier{}, nil
+}
+
+// notifier is a no-op outside Linux.
+type notifier struct{}
+
+// Close is a no-op.
+func (n *notifier) Close() (err error) {
+	return nil
+}
@@ -0,0 +1,32 @@
+package streambuf
+
+import "fmt"
+
+var _ error = &OffsetEvictedError{}
+
+// newOffsetEvictedError constructs an OffsetEvictedError for a read at offset.
+func newOffsetEvictedError(offset, oldest int64) (out *OffsetEvictedError) {
+	var e OffsetEvictedError
+	e.Offset = offset
+	e.Oldest = oldest
+	return &e
+}
+
+// OffsetEvictedError reports a read below the retained window of a bounded
+// backend. Readers can recover by seeking to Oldest.
+type OffsetEvictedError struct {
+	// Offset is the absolute offset that was requested.
+	Offset int64
+	// Oldest is the oldest absolute offset still available.
+	Oldest int64
+}
+
+// Error implements error.
+func (e *OffsetEvictedError) Error() (out string) {
+	return fmt.Sprintf("offset %d has been evicted, oldest available offset is %d", e.Offset, e.Oldest)
+}
+
+// Is reports whether target is ErrOffsetEvicted.
+func (e *OffsetEvictedError) Is(target error) (ok bool) {
+	return target == ErrOffsetEvicted
+}
@@ -0,0 +1,179 @@
+package streambuf
+
+import (
+	"log/slog"
+	"os"
+	"time"
+)
+
+// WithPermissions sets the permissions used when creating backend files.
+// The default is 0644.
+func WithPermissions(perm os.FileMode) (opt Option) {
+	return func(c *config) {
+		c.perm = perm
+	}
+}
+
+// WithInitialCapacity sets the initial byte capacity of memory backends.
+// The default is 1024, which a negative n keeps.
+func WithInitialCapacity(n int) (opt Option) {
+	return func(c *config) {
+		c.kinds |= memoryOption
+		if n < 0 {
+			return
+		}
+
+		c.initialCapacity = n
+	}
+}
+
+// WithRingCapacity bounds memory backends to the newest n bytes, discarding
+// the oldest bytes first. Reads below the retained window return an
+// *OffsetEvictedError.
+func WithRingCapacity(n int64) (opt Option) {
+	return func(c *config) {
+		c.kinds |= memoryOption
+		c.ringCapacity = n
+	}
+}
+
+// WithChunkSize stores memory backends in fixed-size chunks of size bytes
+// instead of one contiguous slice, so growth never copies existing bytes and
+// reads are not blocked by reallocation. WithInitialCapacity is ignored.
+// A non-positive size uses DefaultChunkSize.
+func WithChunkSize(size int) (opt Option) {
+	return func(c *config) {
+		c.kinds |= memoryOption
+		if size <= 0 {
+			size = DefaultChunkSize
+		}
+
+		c.chunkSize = size
+	}
+}
+
+// WithMmap serves file reads from a shared memory mapping, remapped as the file
+// grows, so reads become memory copies instead of syscalls. A copy that faults
+// because another process truncated the file falls back to the file handle.
+// It applies to single-file Buffers and Streams on Linux; elsewhere, or when
+// the file cannot be mapped, reads use the file handle.
+func WithMmap() (opt Option) {
+	return func(c *config) {
+		c.kinds |= mmapOption
+		c.mmap = true
+	}
+}
+
+// WithSegments makes New and NewFileBackend treat their path as a directory
+// of numbered segment files that roll over according to cfg.
+func WithSegments(cfg SegmentConfig) (opt Option) {
+	return func(c *config) {
+		c.kinds |= segmentsOption
+		c.segments = &cfg
+	}
+}
+
+// WithSync applies policy to file and custom backends.
+func WithSync(policy SyncPolicy) (opt Option) {
+	return func(c *config) {
+		c.kinds |= syncOption
+		c.sync = &policy
+	}
+}
+
+// WithRetention discards the head of a Buffer according to policy, evaluated
+// every interval. A non-positive interval uses DefaultRetentionInterval.
+func WithRetention(policy RetentionPolicy, interval time.Duration) (opt Option) {
+	return func(c *config) {
+		if interval <= 0 {
+			interval = DefaultRetentionInterval
+		}
+
+		c.retention = policy
+		c.retentionInterval = interval
+	}
+}
+
+// WithPollInterval sets how often a follow Stream stats its file for growth,
+// truncation, and rotation. On Linux, inotify also wakes readers between polls.
+// A non-positive interval uses DefaultPollInterval.
+func WithPollInterval(interval time.Duration) (opt Option) {
+	return func(c *config) {
+		if interval <= 0 {
+			interval = DefaultPollInterval
+		}
+
+		c.pollInterval = interval
+	}
+}
+
+// WithCursorPath sets the file that persists named reader offsets.
+// File Buffers default to their path with a ".cursors" suffix; other Buffers
+// keep offsets in memory unless a path is set.
+func WithCursorPath(path string) (opt Option) {
+	return func(c *config) {
+		c.cursorPath = path
+	}
+}
+
+// WithAutoCommit makes named readers commit their offset every interval and
+// on Close. Auto-commit may record bytes that were read but not yet processed,
+// so use explicit Commit calls for at-least-once delivery.
+// A non-positive interval disables auto-commit.
+func WithAutoCommit(interval time.Duration) (opt Option) {
+	return func(c *config) {
+		c.autoCommit = interval
+	}
+}
+
+// WithSlowConsumer checks reader lag every policy.Interval and reports or
+// disconnects readers that fall more than policy.MaxLag bytes behind.
+// Constructors that return an error return ErrInvalidInterval for a
+// non-positive interval; NewMemory and NewMemoryStream log it through
+// WithLogger and skip the checks instead.
+func WithSlowConsumer(policy SlowConsumerPolicy) (opt Option) {
+	return func(c *config) {
+		c.slowConsumer = &policy
+	}
+}
+
+// WithBackpressure makes Buffer writes block while the slowest open reader
+// lags more than maxLag bytes behind, waking as readers advance or close.
+// Readers disconnected by WithSlowConsumer no longer hold writers back.
+// Writes are serialized while backpressure is enabled, so concurrent writers
+// exceed maxLag by at most one write. A non-positive maxLag disables backpressure.
+func WithBackpressure(maxLag int64) (opt Option) {
+	return func(c *config) {
+		c.backpressure = maxLag
+	}
+}
+
+// WithLogger sets the logger that reports background task failures.
+// The default discards all records, and a nil logger keeps the default.
+func WithLogger(logger *slog.Logger) (opt Option) {
+	return func(c *config) {
+		if logger == nil {
+			return
+		}
+
+		c.logger = logger
+	}
+}
+
+// WithMetrics sets the receiver of instrumentation events.
+// A nil m keeps the default, which discards them.
+func WithMetrics(m Metrics) (opt Option) {
+	return func(c *config) {
+		if m == nil {
+			return
+		}
+
+		c.metrics = m
+	}
+}
+
+// Option configures a Buffer or Stream at construction. Constructors return
+// ErrUnsupportedOption for storage options that do not apply to them, such as
+// WithRingCapacity for a file or WithSync for memory; constructors that
+// cannot return an error log and ignore them instead.
+type Option func(c *config)
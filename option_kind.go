@@ -0,0 +1,16 @@
+package streambuf
+
+// optionKind marks Options that only apply to some storage, so constructors
+// can reject those they cannot honor with ErrUnsupportedOption.
+type optionKind uint8
+
+const (
+	// memoryOption marks WithInitialCapacity, WithRingCapacity, and WithChunkSize.
+	memoryOption optionKind = 1 << iota
+	// segmentsOption marks WithSegments.
+	segmentsOption
+	// mmapOption marks WithMmap.
+	mmapOption
+	// syncOption marks WithSync.
+	syncOption
+)
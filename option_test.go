@@ -0,0 +1,342 @@
+package streambuf
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testMetrics struct {
+	written atomic.Int64
+	readers atomic.Int64
+}
+
+func (m *testMetrics) Written(n int) {
+	m.written.Add(int64(n))
+}
+
+func (m *testMetrics) ReaderOpened() {
+	m.readers.Add(1)
+}
+
+func (m *testMetrics) ReaderClosed() {
+	m.readers.Add(-1)
+}
+
+func Test_Option(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		opts []Option
+
+		check func(t *testing.T, c *config)
+	}
+
+	tests := []testcase{
+		{
+			name: "defaults",
+			check: func(t *testing.T, c *config) {
+				t.Helper()
+				if c.perm != 0644 || c.initialCapacity != 1024 || c.segments != nil || c.sync != nil {
+					t.Fatalf("newConfig() invalid defaults, received <%+v>", c)
+				}
+			},
+		},
+		{
+			name: "permissions and capacity",
+			opts: []Option{WithPermissions(0600), WithInitialCapacity(64), WithRingCapacity(128)},
+			check: func(t *testing.T, c *config) {
+				t.Helper()
+				if c.perm != 0600 || c.initialCapacity != 64 || c.ringCapacity != 128 {
+					t.Fatalf("newConfig() invalid values, received <%+v>", c)
+				}
+			},
+		},
+		{
+			name: "retention default interval",
+			opts: []Option{WithRetention(MaxBytes(1), 0)},
+			check: func(t *testing.T, c *config) {
+				t.Helper()
+				if c.retentionInterval != DefaultRetentionInterval {
+					t.Fatalf("newConfig() invalid retention interval, expected <%v> and received <%v>", DefaultRetentionInterval, c.retentionInterval)
+				}
+			},
+		},
+		{
+			name: "invalid values keep defaults",
+			opts: []Option{WithInitialCapacity(-1), WithLogger(nil), WithMetrics(nil)},
+			check: func(t *testing.T, c *config) {
+				t.Helper()
+				if c.initialCapacity != 1024 || c.logger == nil || c.metrics == nil {
+					t.Fatalf("newConfig() invalid defaults, received <%+v>", c)
+				}
+			},
+		},
+		{
+			name: "chunk size default",
+			opts: []Option{WithChunkSize(0)},
+			check: func(t *testing.T, c *config) {
+				t.Helper()
+				if c.chunkSize != DefaultChunkSize {
+					t.Fatalf("newConfig() invalid chunk size, expected <%d> and received <%d>", DefaultChunkSize, c.chunkSize)
+				}
+			},
+		},
+		{
+			name: "later options win",
+			opts: []Option{WithSync(SyncPolicy{Mode: SyncNever}), WithSync(SyncPolicy{Mode: SyncEveryWrite})},
+			check: func(t *testing.T, c *config) {
+				t.Helper()
+				if c.sync.Mode != SyncEveryWrite {
+					t.Fatalf("newConfig() invalid sync mode, expected <%v> and received <%v>", SyncEveryWrite, c.sync.Mode)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, newConfig(tt.opts))
+		})
+	}
+}
+
+func Test_unsupported_options(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		open func(filepath string, opt Option) (err error)
+		opts []Option
+	}
+
+	memoryOpts := []Option{WithInitialCapacity(64), WithRingCapacity(64), WithChunkSize(64)}
+	tests := []testcase{
+		{
+			name: "New",
+			open: func(filepath string, opt Option) (err error) {
+				_, err = New(filepath, opt)
+				return err
+			},
+			opts: memoryOpts,
+		},
+		{
+			name: "Open",
+			open: func(filepath string, opt Option) (err error) {
+				_, _, err = Open(filepath, RecoverTruncate, opt)
+				return err
+			},
+			opts: append([]Option{WithSegments(SegmentConfig{})}, memoryOpts...),
+		},
+		{
+			name: "NewFileBackend",
+			open: func(filepath string, opt Option) (err error) {
+				_, _, err = NewFileBackend(filepath, opt)
+				return err
+			},
+			opts: append([]Option{WithSync(SyncPolicy{})}, memoryOpts...),
+		},
+		{
+			name: "NewStream",
+			open: func(filepath string, opt Option) (err error) {
+				_, err = NewStream(filepath, opt)
+				return err
+			},
+			opts: append([]Option{WithSync(SyncPolicy{}), WithSegments(SegmentConfig{})}, memoryOpts...),
+		},
+		{
+			name: "NewFollowStream",
+			open: func(filepath string, opt Option) (err error) {
+				_, err = NewFollowStream(filepath, opt)
+				return err
+			},
+			opts: append([]Option{WithSync(SyncPolicy{}), WithSegments(SegmentConfig{}), WithMmap()}, memoryOpts...),
+		},
+		{
+			name: "NewStreamWithBackend",
+			open: func(filepath string, opt Option) (err error) {
+				_, err = NewStreamWithBackend(newReadableMemory(newMemory(nil)), opt)
+				return err
+			},
+			opts: append([]Option{WithSync(SyncPolicy{}), WithSegments(SegmentConfig{}), WithMmap()}, memoryOpts...),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, opt := range tt.opts {
+				filepath := t.TempDir() + "/buffer.tmp"
+				if err := os.WriteFile(filepath, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := tt.open(filepath, opt); !errors.Is(err, ErrUnsupportedOption) {
+					t.Fatalf("%s() invalid error for option %d, expected <%v> and received <%v>", tt.name, i, ErrUnsupportedOption, err)
+				}
+			}
+		})
+	}
+}
+
+func Test_NewMemory_unsupported_options(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		opt Option
+	}
+
+	tests := []testcase{
+		{name: "WithSync", opt: WithSync(SyncPolicy{})},
+		{name: "WithSegments", opt: WithSegments(SegmentConfig{})},
+		{name: "WithMmap", opt: WithMmap()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, nil))
+			b := NewMemory(tt.opt, WithLogger(logger))
+			t.Cleanup(func() {
+				_ = b.Close()
+			})
+
+			if !strings.Contains(buf.String(), ErrUnsupportedOption.Error()) {
+				t.Fatalf("NewMemory() invalid log, expected <%v> and received <%v>", ErrUnsupportedOption, buf.String())
+			}
+		})
+	}
+}
+
+func Test_New_WithPermissions(t *testing.T) {
+	var (
+		filepath string
+		b        *Buffer
+		info     os.FileInfo
+		err      error
+	)
+
+	filepath = t.TempDir() + "/buffer-permissions.tmp"
+	if b, err = New(filepath, WithPermissions(0600)); err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	if info, err = os.Stat(filepath); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("New() invalid file permissions, expected <%v> and received <%v>", os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func Test_Open_WithPermissions_checkpoint(t *testing.T) {
+	var (
+		filepath string
+		b        *Buffer
+		info     os.FileInfo
+		err      error
+	)
+
+	filepath = t.TempDir() + "/buffer-permissions.tmp"
+	if b, _, err = Open(filepath, RecoverReport, WithPermissions(0600)); err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	if _, err = b.Write([]byte("hello world")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if err = b.Truncate(6); err != nil {
+		t.Fatalf("Truncate() unexpected error: %v", err)
+	}
+
+	if info, err = os.Stat(filepath + checkpointExt); err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("Truncate() invalid checkpoint permissions, expected <%v> and received <%v>", os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func Test_NewMemory_WithMetrics(t *testing.T) {
+	var (
+		m   testMetrics
+		b   *Buffer
+		r   io.ReadSeekCloser
+		err error
+	)
+
+	b = NewMemory(WithMetrics(&m))
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	if _, err = b.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if r, err = b.Reader(); err != nil {
+		t.Fatalf("Reader() unexpected error: %v", err)
+	}
+
+	if got := m.readers.Load(); got != 1 {
+		t.Fatalf("ReaderOpened() invalid count, expected <1> and received <%v>", got)
+	}
+
+	if err = r.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	if got := m.readers.Load(); got != 0 {
+		t.Fatalf("ReaderClosed() invalid count, expected <0> and received <%v>", got)
+	}
+
+	if got := m.written.Load(); got != 5 {
+		t.Fatalf("Written() invalid count, expected <5> and received <%v>", got)
+	}
+}
+
+func Test_periodic_WithLogger(t *testing.T) {
+	var (
+		buf  bytes.Buffer
+		p    *periodic
+		runs atomic.Int64
+	)
+
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	p = newPeriodic("test", time.Millisecond, logger, func(now time.Time) (err error) {
+		if runs.Add(1) == 1 {
+			return errors.New("transient failure")
+		}
+
+		return ErrIsClosed
+	})
+
+	deadline := time.Now().Add(time.Second)
+	for runs.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("periodic task did not retry after a failure")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	_ = p.Close()
+	if !strings.Contains(buf.String(), "transient failure") {
+		t.Fatalf("periodic task did not log failure, received <%v>", buf.String())
+	}
+}
@@ -0,0 +1,13 @@
+package streambuf
+
+// Peeker is implemented by the readers returned from Reader and
+// StreamingReader. It exposes unread bytes without copying them from memory
+// backends; other backends copy into a new slice.
+type Peeker interface {
+	// Peek returns up to n of the next unread bytes without advancing the
+	// reader, waiting like Read when none are available. The returned slice
+	// must not be modified and is only valid until the reader advances.
+	Peek(n int) (out []byte, err error)
+	// Next behaves like Peek but advances the reader past the returned bytes.
+	Next(n int) (out []byte, err error)
+}
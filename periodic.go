@@ -0,0 +1,60 @@
+package streambuf
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// newPeriodic starts calling fn every interval until the periodic is closed
+// or fn returns ErrIsClosed. Other errors are logged and retried on the next interval.
+func newPeriodic(name string, interval time.Duration, logger *slog.Logger, fn func(now time.Time) (err error)) (out *periodic) {
+	var p periodic
+	p.name = name
+	p.logger = logger
+	p.stop = newWaiter()
+	go p.run(interval, fn)
+	return &p
+}
+
+// periodic runs a background task on a fixed interval.
+type periodic struct {
+	name   string
+	logger *slog.Logger
+
+	stop *waiter
+}
+
+// run invokes fn on every tick until stopped.
+func (p *periodic) run(interval time.Duration, fn func(now time.Time) (err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop.Wait():
+			return
+		case now := <-ticker.C:
+			if !p.handle(fn(now)) {
+				return
+			}
+		}
+	}
+}
+
+// handle logs a failed run and reports whether the task should keep running.
+func (p *periodic) handle(err error) (ok bool) {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrIsClosed):
+		return false
+	default:
+		p.logger.Warn("streambuf: periodic task failed", "task", p.name, "error", err)
+		return true
+	}
+}
+
+// Close stops the periodic task without waiting for an in-flight call.
+func (p *periodic) Close() (err error) {
+	return p.stop.Close()
+}
@@ -0,0 +1,46 @@
+//go:build linux
+
+package streambuf
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+const (
+	fallocKeepSize  = 0x01
+	fallocPunchHole = 0x02
+)
+
+// punchHole deallocates the byte range [0, offset) of f without changing its size.
+// Filesystems without hole punching support leave the bytes in place.
+func punchHole(f *os.File, offset int64) (err error) {
+	if offset <= 0 {
+		return nil
+	}
+
+	var (
+		conn     syscall.RawConn
+		punchErr error
+	)
+
+	if conn, err = f.SyscallConn(); err != nil {
+		return fmt.Errorf("punch hole in writer file: %w", err)
+	}
+
+	err = conn.Control(func(fd uintptr) {
+		punchErr = syscall.Fallocate(int(fd), fallocKeepSize|fallocPunchHole, 0, offset)
+	})
+
+	switch {
+	case err != nil:
+		return fmt.Errorf("punch hole in writer file: %w", err)
+	case punchErr == syscall.EOPNOTSUPP:
+		return nil
+	case punchErr != nil:
+		return fmt.Errorf("punch hole in writer file: %w", punchErr)
+	default:
+		return nil
+	}
+}
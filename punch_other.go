@@ -0,0 +1,11 @@
+//go:build !linux
+
+package streambuf
+
+import "os"
+
+// punchHole is a no-op on platforms without hole punching support.
+// The low-water mark still prevents reads of the discarded bytes.
+func punchHole(f *os.File, offset int64) (err error) {
+	return nil
+}
@@ -0,0 +1,10 @@
+package streambuf
+
+import "io"
+
+// rangeWriter is implemented by backends that can write a byte range to an
+// io.Writer directly, letting the kernel copy file bytes to sockets.
+type rangeWriter interface {
+	// writeRangeTo writes the n bytes starting at index to w.
+	writeRangeTo(w io.Writer, index, n int64) (written int64, err error)
+}
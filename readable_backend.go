@@ -0,0 +1,26 @@
+package streambuf
+
+// ReadableBackend is the read side of Buffer and Stream storage.
+// Implementations must be safe for concurrent use, including concurrent use
+// with their WritableBackend, and must observe every byte once the
+// corresponding Write returns.
+//
+// ReadAt copies bytes starting at the absolute index into in. It returns the
+// number of bytes copied with a nil error whenever at least one byte is
+// available; short reads are allowed. When no bytes are available at index it
+// returns:
+//
+//   - io.EOF, optionally wrapped, when the backend is open and index is at or
+//     past Size.
+//   - ErrIsClosed when the backend has been closed.
+//   - An *OffsetEvictedError when index is below the retained bytes.
+//
+// Size returns the absolute offset immediately after the last readable byte,
+// or ErrIsClosed once the backend is closed.
+//
+// Close releases the backend and returns ErrIsClosed when called again.
+type ReadableBackend interface {
+	ReadAt(in []byte, index int64) (n int, err error)
+	Size() (n int64, err error)
+	Close() (err error)
+}
@@ -0,0 +1,97 @@
+package streambuf
+
+import (
+	"io"
+	"sync"
+)
+
+var (
+	_ ReadableBackend = &readableChunks{}
+	_ viewer          = &readableChunks{}
+)
+
+// newReadableChunks constructs the readable backend over chunked memory.
+func newReadableChunks(in *chunks) (out *readableChunks) {
+	var m readableChunks
+	m.c = in
+	return &m
+}
+
+// readableChunks is a readable memory backend that shares chunked storage.
+type readableChunks struct {
+	mux sync.RWMutex
+
+	c *chunks
+
+	closed bool
+}
+
+// ReadAt copies bytes from index into in, spanning chunks as needed.
+// It returns ErrIsClosed when no bytes are available and the backend is closed.
+// It returns an *OffsetEvictedError when index falls before the retained bytes.
+func (m *readableChunks) ReadAt(in []byte, index int64) (n int, err error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	var (
+		low int64
+		ok  bool
+	)
+
+	switch n, low, ok = m.c.readAt(in, index); {
+	case !ok:
+		return 0, newOffsetEvictedError(index, low)
+	case n > 0:
+		return n, nil
+	case m.closed:
+		return 0, ErrIsClosed
+	default:
+		return 0, io.EOF
+	}
+}
+
+// viewAt returns up to n bytes from index without copying, ending at the
+// chunk boundary. Chunks are never modified once written.
+func (m *readableChunks) viewAt(index int64, n int) (out []byte, err error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	var (
+		low int64
+		ok  bool
+	)
+
+	switch out, low, ok = m.c.viewAt(index, n); {
+	case !ok:
+		return nil, newOffsetEvictedError(index, low)
+	case len(out) > 0:
+		return out, nil
+	case m.closed:
+		return nil, ErrIsClosed
+	default:
+		return nil, io.EOF
+	}
+}
+
+// Size returns the absolute offset immediately after the last retained byte.
+func (m *readableChunks) Size() (n int64, err error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	if m.closed {
+		return 0, ErrIsClosed
+	}
+
+	return m.c.end(), nil
+}
+
+// Close marks the readable chunked backend as closed.
+func (m *readableChunks) Close() (err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed {
+		return ErrIsClosed
+	}
+
+	m.closed = true
+	return nil
+}
@@ -0,0 +1,53 @@
+package streambuf
+
+import "io"
+
+var _ ReadableBackend = &readableCommitted{}
+
+// newReadableCommitted wraps r so reads stop at the committed offset of w.
+func newReadableCommitted(r ReadableBackend, w *writableSync) (out *readableCommitted) {
+	var rc readableCommitted
+	rc.r = r
+	rc.w = w
+	return &rc
+}
+
+// readableCommitted is a readable wrapper that hides bytes which have not been synced.
+type readableCommitted struct {
+	r ReadableBackend
+	w *writableSync
+}
+
+// ReadAt copies committed bytes from index into in.
+// It returns io.EOF when index is at or past the committed offset, or the
+// wrapped backend's error when it is closed.
+func (r *readableCommitted) ReadAt(in []byte, index int64) (n int, err error) {
+	committed := r.w.Committed()
+	if index >= committed {
+		if _, err = r.r.Size(); err != nil {
+			return 0, err
+		}
+
+		return 0, io.EOF
+	}
+
+	if int64(len(in)) > committed-index {
+		in = in[:committed-index]
+	}
+
+	return r.r.ReadAt(in, index)
+}
+
+// Size returns the committed offset.
+func (r *readableCommitted) Size() (n int64, err error) {
+	if _, err = r.r.Size(); err != nil {
+		return 0, err
+	}
+
+	return r.w.Committed(), nil
+}
+
+// Close closes the wrapped backend.
+func (r *readableCommitted) Close() (err error) {
+	return r.r.Close()
+}
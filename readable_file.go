@@ -2,11 +2,15 @@ package streambuf
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sync"
 )
 
-var _ readable = &readableFile{}
+var (
+	_ ReadableBackend = &readableFile{}
+	_ rangeWriter     = &readableFile{}
+)
 
 // newReadableFile constructs a readable file backend for an existing file path.
 func newReadableFile(filepath string) (out *readableFile, err error) {
@@ -43,6 +47,55 @@ func (f *readableFile) ReadAt(in []byte, index int64) (n int, err error) {
 	}
 }
 
+// writeRangeTo writes the n bytes starting at index to w through a separate
+// handle, so io.Copy can use sendfile or splice when w is a socket or pipe.
+func (f *readableFile) writeRangeTo(w io.Writer, index, n int64) (written int64, err error) {
+	var name string
+	if name, err = f.name(); err != nil {
+		return 0, err
+	}
+
+	var src *os.File
+	if src, err = os.Open(name); err != nil {
+		return 0, fmt.Errorf("open reader file: %w", err)
+	}
+	defer src.Close()
+
+	if _, err = src.Seek(index, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("seek reader file to index %d: %w", index, err)
+	}
+
+	return io.Copy(w, io.LimitReader(src, n))
+}
+
+// name returns the path the file was opened with.
+// It returns ErrIsClosed if the readable file is closed.
+func (f *readableFile) name() (name string, err error) {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	if f.closed {
+		return "", ErrIsClosed
+	}
+
+	return f.f.Name(), nil
+}
+
+// Size returns the current size of the underlying file.
+func (f *readableFile) Size() (n int64, err error) {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	if f.closed {
+		return 0, ErrIsClosed
+	}
+
+	var info os.FileInfo
+	if info, err = f.f.Stat(); err != nil {
+		return 0, fmt.Errorf("stat reader file: %w", err)
+	}
+
+	return info.Size(), nil
+}
+
 // Close marks the readable file as closed and closes its file handle.
 func (f *readableFile) Close() (err error) {
 	f.mux.Lock()
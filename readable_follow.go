@@ -0,0 +1,124 @@
+package streambuf
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+var _ ReadableBackend = &readableFollow{}
+
+// newReadableFollow constructs a readable backend for a file that another
+// process may append to, truncate, or replace.
+func newReadableFollow(filepath string) (out *readableFollow, err error) {
+	var f readableFollow
+	f.path = filepath
+	if f.f, err = os.Open(filepath); err != nil {
+		return nil, fmt.Errorf("open follow file: %w", err)
+	}
+
+	return &f, nil
+}
+
+// readableFollow is a read-only file backend whose handle can be swapped
+// when the followed path is rotated.
+type readableFollow struct {
+	mux sync.RWMutex
+
+	path string
+	f    *os.File
+
+	closed bool
+}
+
+// ReadAt copies bytes from index into in.
+// It returns ErrIsClosed when no bytes are read and the backend is closed.
+func (f *readableFollow) ReadAt(in []byte, index int64) (n int, err error) {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	if f.closed {
+		return 0, ErrIsClosed
+	}
+
+	if n, err = f.f.ReadAt(in, index); n > 0 {
+		return n, nil
+	}
+
+	return 0, fmt.Errorf("read follow file at index %d: %w", index, err)
+}
+
+// Size returns the current size of the open file handle.
+func (f *readableFollow) Size() (n int64, err error) {
+	var info os.FileInfo
+	if info, _, err = f.stat(); err != nil {
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+// Close marks the backend as closed and closes its file handle.
+func (f *readableFollow) Close() (err error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if f.closed {
+		return ErrIsClosed
+	}
+
+	f.closed = true
+
+	if err = f.f.Close(); err != nil {
+		return fmt.Errorf("close follow file: %w", err)
+	}
+
+	return nil
+}
+
+// stat returns the info of the open handle and of the file currently at the
+// path. atPath is nil while the path is missing, such as mid-rotation.
+func (f *readableFollow) stat() (open, atPath os.FileInfo, err error) {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	if f.closed {
+		return nil, nil, ErrIsClosed
+	}
+
+	if open, err = f.f.Stat(); err != nil {
+		return nil, nil, fmt.Errorf("stat follow file: %w", err)
+	}
+
+	switch atPath, err = os.Stat(f.path); {
+	case err == nil:
+		return open, atPath, nil
+	case os.IsNotExist(err):
+		return open, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("stat follow path: %w", err)
+	}
+}
+
+// reopen swaps the handle for the file currently at the path. onSwap runs
+// while reads are excluded, so no read observes the new file before onSwap returns.
+func (f *readableFollow) reopen(onSwap func()) (err error) {
+	var next *os.File
+	if next, err = os.Open(f.path); err != nil {
+		return fmt.Errorf("reopen follow file: %w", err)
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if f.closed {
+		_ = next.Close()
+		return ErrIsClosed
+	}
+
+	prev := f.f
+	f.f = next
+	onSwap()
+
+	if err = prev.Close(); err != nil {
+		return fmt.Errorf("close rotated follow file: %w", err)
+	}
+
+	return nil
+}
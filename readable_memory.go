@@ -5,7 +5,10 @@ import (
 	"sync"
 )
 
-var _ readable = &readableMemory{}
+var (
+	_ ReadableBackend = &readableMemory{}
+	_ viewer          = &readableMemory{}
+)
 
 // newReadableMemory constructs the readable memory backend used by Buffer and Stream.
 func newReadableMemory(in *memory) (out *readableMemory) {
@@ -25,13 +28,16 @@ type readableMemory struct {
 
 // ReadAt copies bytes from index into in.
 // It returns ErrIsClosed when no bytes are available and the readable memory is closed.
+// It returns an *OffsetEvictedError when index falls before the retained bytes.
 func (m *readableMemory) ReadAt(in []byte, index int64) (n int, err error) {
 	m.mux.RLock()
 	defer m.mux.RUnlock()
-	m.m.read(func(bs []byte) {
+	m.m.read(func(bs []byte, offset int64) {
 		switch {
-		case index < int64(len(bs)):
-			n = copy(in, bs[index:])
+		case index < offset:
+			err = newOffsetEvictedError(index, offset)
+		case index-offset < int64(len(bs)):
+			n = copy(in, bs[index-offset:])
 		case m.closed:
 			err = ErrIsClosed
 		default:
@@ -42,6 +48,44 @@ func (m *readableMemory) ReadAt(in []byte, index int64) (n int, err error) {
 	return n, err
 }
 
+// viewAt returns up to n retained bytes from index without copying.
+// Appends never modify retained bytes, so the view stays valid after the lock is released.
+func (m *readableMemory) viewAt(index int64, n int) (out []byte, err error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	m.m.read(func(bs []byte, offset int64) {
+		switch {
+		case index < offset:
+			err = newOffsetEvictedError(index, offset)
+		case index-offset < int64(len(bs)):
+			start := index - offset
+			end := min(start+int64(n), int64(len(bs)))
+			out = bs[start:end:end]
+		case m.closed:
+			err = ErrIsClosed
+		default:
+			err = io.EOF
+		}
+	})
+
+	return out, err
+}
+
+// Size returns the absolute offset immediately after the last retained byte.
+func (m *readableMemory) Size() (n int64, err error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	if m.closed {
+		return 0, ErrIsClosed
+	}
+
+	m.m.read(func(bs []byte, offset int64) {
+		n = offset + int64(len(bs))
+	})
+
+	return n, nil
+}
+
 // Close marks the readable memory backend as closed.
 func (m *readableMemory) Close() (err error) {
 	m.mux.Lock()
@@ -0,0 +1,171 @@
+//go:build linux
+
+package streambuf
+
+import (
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+	"syscall"
+)
+
+// minMmapCapacity is the smallest mapping, so small growing files are not
+// remapped on every append.
+const minMmapCapacity = 1 << 20
+
+var (
+	_ ReadableBackend = &readableMmap{}
+	_ rangeWriter     = &readableMmap{}
+)
+
+// newReadableMmap constructs a readable file backend that serves reads from a
+// shared memory mapping of filepath.
+func newReadableMmap(filepath string) (out ReadableBackend, err error) {
+	var m readableMmap
+	if m.file, err = newReadableFile(filepath); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// readableMmap is a read-only file backend that copies from a memory mapping
+// instead of issuing a pread per read. The mapping may extend past the end of
+// the file and is replaced once the file outgrows it. When the file cannot be
+// mapped, or a copy faults because the file was truncated underneath the
+// mapping, reads fall back to the file handle.
+type readableMmap struct {
+	mux sync.RWMutex
+
+	file *readableFile
+	data []byte
+	// size is the file size last observed, bounding reads from data.
+	size int64
+	// unmappable is set once mapping fails, making every read use file.
+	unmappable bool
+
+	closed bool
+}
+
+// ReadAt copies bytes from index into in.
+// It returns ErrIsClosed when no bytes are read and the backend is closed.
+func (m *readableMmap) ReadAt(in []byte, index int64) (n int, err error) {
+	var ok bool
+	if n, ok = m.readMapped(in, index); ok {
+		return n, nil
+	}
+
+	if err = m.grow(); err != nil {
+		return 0, err
+	}
+
+	if n, ok = m.readMapped(in, index); ok {
+		return n, nil
+	}
+
+	// Reads at the end, or of a file that cannot be mapped, use the file
+	// handle, which reports EOF and close state.
+	return m.file.ReadAt(in, index)
+}
+
+// writeRangeTo writes the n bytes starting at index to w through the file
+// handle, so io.Copy can use sendfile or splice when w is a socket or pipe.
+func (m *readableMmap) writeRangeTo(w io.Writer, index, n int64) (written int64, err error) {
+	return m.file.writeRangeTo(w, index, n)
+}
+
+// Size returns the current size of the underlying file.
+func (m *readableMmap) Size() (n int64, err error) {
+	return m.file.Size()
+}
+
+// Close unmaps the file and closes its file handle.
+func (m *readableMmap) Close() (err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed {
+		return ErrIsClosed
+	}
+
+	m.closed = true
+	m.unmap()
+	return m.file.Close()
+}
+
+// readMapped copies mapped bytes from index into in. ok is false when index
+// is outside the bytes known to be mapped, or when the copy faults because
+// another process truncated the file, leaving the read to the file handle.
+func (m *readableMmap) readMapped(in []byte, index int64) (n int, ok bool) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	if m.closed || index < 0 || index >= m.size {
+		return 0, false
+	}
+
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if recover() != nil {
+			n, ok = 0, false
+		}
+	}()
+
+	return copy(in, m.data[index:m.size]), true
+}
+
+// grow observes the current file size and remaps when the file has outgrown
+// the mapping.
+func (m *readableMmap) grow() (err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed || m.unmappable {
+		return nil
+	}
+
+	var size int64
+	if size, err = m.file.Size(); err != nil {
+		return err
+	}
+
+	switch {
+	case size == m.size:
+		return nil
+	case size <= int64(len(m.data)):
+		// A smaller size means the file was truncated, so only the bytes
+		// still backed by the file may be copied from the mapping.
+		m.size = size
+		return nil
+	}
+
+	m.unmap()
+
+	var conn syscall.RawConn
+	if conn, err = m.file.f.SyscallConn(); err != nil {
+		return fmt.Errorf("map file: %w", err)
+	}
+
+	// Control keeps the descriptor non-blocking, unlike Fd.
+	capacity := max(size+size/2, minMmapCapacity)
+	var mapErr error
+	if err = conn.Control(func(fd uintptr) {
+		m.data, mapErr = syscall.Mmap(int(fd), 0, int(capacity), syscall.PROT_READ, syscall.MAP_SHARED)
+	}); err != nil || mapErr != nil {
+		m.unmappable = true
+		m.data = nil
+		return nil
+	}
+
+	m.size = size
+	return nil
+}
+
+// unmap releases the current mapping, if any. The caller must hold the write lock.
+func (m *readableMmap) unmap() {
+	if m.data == nil {
+		return
+	}
+
+	_ = syscall.Munmap(m.data)
+	m.data = nil
+	m.size = 0
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package streambuf
+
+// newReadableMmap falls back to a pread-based readable file on platforms
+// without mmap support.
+func newReadableMmap(filepath string) (out ReadableBackend, err error) {
+	var f *readableFile
+	if f, err = newReadableFile(filepath); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
@@ -0,0 +1,178 @@
+package streambuf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_readableMmap_ReadAt(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		// appends are written to the file one at a time, each followed by a
+		// read of the bytes it added.
+		appends [][]byte
+	}
+
+	tests := []testcase{
+		{
+			name:    "single write",
+			appends: [][]byte{[]byte("hello")},
+		},
+		{
+			name:    "growth within mapping",
+			appends: [][]byte{[]byte("hello"), []byte(" world")},
+		},
+		{
+			// 3 MiB outgrows the smallest mapping on Linux.
+			name:    "growth beyond mapping",
+			appends: [][]byte{[]byte("hello"), bytes.Repeat([]byte("x"), 3<<20), []byte("tail")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				w     *writableFile
+				r     ReadableBackend
+				index int64
+				err   error
+			)
+
+			path := filepath.Join(t.TempDir(), "buffer.tmp")
+			if w, err = newWritableFile(path, 0644); err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+
+			if r, err = newReadableMmap(path); err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			for _, bs := range tt.appends {
+				if _, err = w.Write(bs); err != nil {
+					t.Fatal(err)
+				}
+
+				got := make([]byte, len(bs))
+				if _, err = io.ReadFull(io.NewSectionReader(r, index, int64(len(bs))), got); err != nil {
+					t.Fatalf("ReadAt() unexpected error: %v", err)
+				}
+
+				if !bytes.Equal(got, bs) {
+					t.Fatalf("ReadAt() invalid value at index %d", index)
+				}
+
+				index += int64(len(bs))
+			}
+
+			if _, err = r.ReadAt(make([]byte, 1), index); !errors.Is(err, io.EOF) {
+				t.Fatalf("ReadAt() invalid error, expected <%v> and received <%v>", io.EOF, err)
+			}
+
+			if err = r.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = r.ReadAt(make([]byte, 1), 0); !errors.Is(err, ErrIsClosed) {
+				t.Fatalf("ReadAt() invalid error, expected <%v> and received <%v>", ErrIsClosed, err)
+			}
+		})
+	}
+}
+
+func Benchmark_readable_ReadAt(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "buffer.tmp")
+	if err := os.WriteFile(path, make([]byte, 1<<20), 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, size := range []int{64, 4096} {
+		for _, bc := range readableBenchmarks() {
+			b.Run(fmt.Sprintf("%s/%d", bc.name, size), func(b *testing.B) {
+				var (
+					r   ReadableBackend
+					err error
+				)
+
+				if r, err = bc.open(path); err != nil {
+					b.Fatal(err)
+				}
+				defer r.Close()
+
+				out := make([]byte, size)
+				b.SetBytes(int64(size))
+				b.ResetTimer()
+				for i := range b.N {
+					if _, err = r.ReadAt(out, int64(i*size)%(1<<20)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+type readableBenchmark struct {
+	name string
+	open func(path string) (r ReadableBackend, err error)
+}
+
+func readableBenchmarks() (out []readableBenchmark) {
+	return []readableBenchmark{
+		{
+			name: "file",
+			open: func(path string) (r ReadableBackend, err error) {
+				return newReadableFile(path)
+			},
+		},
+		{
+			name: "mmap",
+			open: newReadableMmap,
+		},
+	}
+}
+
+func Test_readableMmap_ReadAt_truncated(t *testing.T) {
+	var (
+		r    ReadableBackend
+		got  []byte
+		n    int
+		path string
+		err  error
+	)
+
+	path = filepath.Join(t.TempDir(), "buffer.tmp")
+	if err = os.WriteFile(path, bytes.Repeat([]byte("x"), 4*os.Getpagesize()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if r, err = newReadableMmap(path); err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	got = make([]byte, 1)
+	if _, err = r.ReadAt(got, 0); err != nil {
+		t.Fatalf("ReadAt() unexpected error: %v", err)
+	}
+
+	// Another process shrinking the file must not crash readers of the mapping.
+	if err = os.Truncate(path, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = r.ReadAt(got, int64(2*os.Getpagesize())); !errors.Is(err, io.EOF) {
+		t.Fatalf("ReadAt() invalid error, expected <%v> and received <%v>", io.EOF, err)
+	}
+
+	if n, err = r.ReadAt(got, 0); err != nil || n != 1 || got[0] != 'x' {
+		t.Fatalf("ReadAt() invalid value, expected <%v> and received <%v> (%v)", "x", string(got[:n]), err)
+	}
+}
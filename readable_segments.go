@@ -0,0 +1,59 @@
+package streambuf
+
+import (
+	"sync"
+)
+
+var _ ReadableBackend = &readableSegments{}
+
+// newReadableSegments constructs a readable backend spanning a directory of segment files.
+func newReadableSegments(s *segments) (out *readableSegments) {
+	var r readableSegments
+	r.s = s
+	return &r
+}
+
+// readableSegments is a read-only backend that maps global offsets to segment files.
+type readableSegments struct {
+	mux sync.RWMutex
+
+	s *segments
+
+	closed bool
+}
+
+// ReadAt copies bytes from index into in, reading from the segment that holds index.
+// It returns ErrIsClosed when no bytes are read and the readable segments are closed.
+// It returns an *OffsetEvictedError when index falls within a removed segment.
+func (r *readableSegments) ReadAt(in []byte, index int64) (n int, err error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	if r.closed {
+		return 0, ErrIsClosed
+	}
+
+	return r.s.readAt(in, index)
+}
+
+// Size returns the absolute offset immediately after the last written byte.
+func (r *readableSegments) Size() (n int64, err error) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	if r.closed {
+		return 0, ErrIsClosed
+	}
+
+	return r.s.end(), nil
+}
+
+// Close marks the readable segments as closed and closes every segment read handle.
+func (r *readableSegments) Close() (err error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.closed {
+		return ErrIsClosed
+	}
+
+	r.closed = true
+	return r.s.close()
+}
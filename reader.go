@@ -1,18 +1,38 @@
 package streambuf
 
 import (
+	"context"
 	"errors"
 	"io"
+	"os"
+	"sync/atomic"
+	"time"
 )
 
-var _ io.ReadSeekCloser = &reader{}
+const (
+	// maxWriteToRange bounds the bytes WriteTo hands to the writer at once.
+	maxWriteToRange = 1 << 30
+	// writeToBufferSize is the copy buffer WriteTo uses for backends that
+	// cannot write ranges or expose views.
+	writeToBufferSize = 32 << 10
+)
+
+var (
+	_ ContextReader = &reader{}
+	_ Peeker        = &reader{}
+	_ io.WriterTo   = &reader{}
+)
 
 // newReader constructs a reader bound to a shared stream.
 func newReader(s *stream, tail bool) (out *reader) {
 	var r reader
 	r.s = s
 	r.tail = tail
+	r.generation = s.generation()
 	r.closer = newWaiter()
+	r.deadline = newDeadline()
+	r.disconnect = newWaiter()
+	r.lastRead.Store(time.Now().UnixNano())
 	return &r
 }
 
@@ -20,10 +40,27 @@ func newReader(s *stream, tail bool) (out *reader) {
 type reader struct {
 	s *stream
 
+	// id is assigned when the reader is registered with its stream.
+	id uint64
+
 	index int64
 	tail  bool
 
-	closer *waiter
+	// position mirrors index so the reader can be inspected while it reads.
+	position atomic.Int64
+	// lastRead holds the unix nanoseconds of the last read that returned bytes.
+	lastRead atomic.Int64
+
+	// generation is the last stream rewind this reader has observed.
+	generation uint64
+
+	closer   *waiter
+	deadline *deadline
+	// disconnect is closed when the reader is dropped as a slow consumer.
+	disconnect *waiter
+
+	// detached readers are not checked out, so CloseAndWait does not wait for them.
+	detached bool
 }
 
 // Read copies available bytes into in.
@@ -33,47 +70,127 @@ type reader struct {
 // A zero-length read returns (0, nil) immediately.
 // Tail readers return EOF when no bytes are read after the stream closes.
 // Tail readers return ErrIsClosed when no bytes are read after the reader closes.
+// Reads return os.ErrDeadlineExceeded once the read deadline passes.
+// Reads return ErrSlowConsumer once a SlowConsumerPolicy disconnects the reader.
+// Readers of a follow Stream return ErrTruncated or ErrRotated once per change
+// to the followed file and continue from offset 0.
 func (r *reader) Read(in []byte) (n int, err error) {
+	return r.ReadContext(context.Background(), in)
+}
+
+// ReadContext behaves like Read but returns ctx.Err() once ctx is done.
+// The reader offset is unchanged by a canceled read, so the reader remains usable.
+func (r *reader) ReadContext(ctx context.Context, in []byte) (n int, err error) {
 	if len(in) == 0 {
 		return 0, nil
 	}
 
-	for {
-		n, err = r.s.r.ReadAt(in, r.index)
-		switch {
-		case n > 0:
-			r.index += int64(n)
-			return n, err
-		case err == nil:
-		case r.s.isClosed() && r.tail:
-			return n, io.EOF
-		case errors.Is(err, io.EOF) && r.tail:
+	if n, err = r.await(ctx, func(index int64) (n int, err error) {
+		return r.s.readVisible(index, len(in), func(n int) (read int, err error) {
+			return r.s.r.ReadAt(in[:n], index)
+		})
+	}); err != nil {
+		return 0, err
+	}
 
-		default:
-			return 0, err
+	r.advance(n)
+	return n, nil
+}
+
+// Peek returns up to n of the next unread bytes without advancing the reader,
+// waiting and failing like Read. Memory backends return a view of the stored
+// bytes without copying; the view must not be modified and is only valid
+// until the reader advances. Other backends copy into a new slice.
+func (r *reader) Peek(n int) (out []byte, err error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	if _, err = r.await(context.Background(), func(index int64) (read int, err error) {
+		return r.s.readVisible(index, n, func(n int) (read int, err error) {
+			out, err = r.view(index, n)
+			return len(out), err
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Next behaves like Peek but advances the reader past the returned bytes.
+func (r *reader) Next(n int) (out []byte, err error) {
+	if out, err = r.Peek(n); err != nil {
+		return nil, err
+	}
+
+	r.advance(len(out))
+	return out, nil
+}
+
+// WriteTo writes unread bytes to w until the reader reaches EOF, which is not
+// returned as an error. Tail readers keep writing until the stream closes.
+// File backends write through a separate file handle, so io.Copy to a socket
+// can use sendfile or splice, and memory backends write without copying.
+func (r *reader) WriteTo(w io.Writer) (n int64, err error) {
+	rw, direct := r.s.r.(rangeWriter)
+
+	// Backends that can neither write ranges nor expose views are copied
+	// through a single buffer.
+	var buf []byte
+	if _, ok := r.s.r.(viewer); !ok && !direct {
+		buf = make([]byte, writeToBufferSize)
+	}
+
+	var written int64
+	for {
+		if direct {
+			written, err = r.writeRange(w, rw)
+		} else {
+			written, err = r.writeView(w, buf)
 		}
 
-		select {
-		case <-r.closer.Wait():
-			return 0, ErrIsClosed
-		case <-r.s.waiter.Wait():
+		n += written
+		switch {
+		case errors.Is(err, io.EOF):
+			return n, nil
+		case err != nil:
+			return n, err
 		}
 	}
 }
 
+// SetReadDeadline implements ContextReader.
+func (r *reader) SetReadDeadline(t time.Time) (err error) {
+	r.deadline.Set(t)
+	return nil
+}
+
 // Seek updates the reader offset using whence semantics.
 // SeekStart sets the absolute position to offset, SeekCurrent moves relative
-// to the current position, and SeekEnd returns ErrSeekEndNotSupported.
+// to the current position, and SeekEnd moves relative to the current size.
+// Seeking a StreamingReader to the end positions it to receive only future writes.
 // If the computed position is negative, the position is clamped to 0 and
-// ErrNegativeIndex is returned.
+// ErrNegativeIndex is returned. If the computed position is below the
+// low-water mark, the position is clamped to the mark and an
+// *OffsetEvictedError is returned.
 func (r *reader) Seek(offset int64, whence int) (pos int64, err error) {
+	defer func() {
+		r.position.Store(r.index)
+		r.s.notifyAdvanced()
+	}()
 	switch whence {
 	case io.SeekStart:
 		r.index = offset
 	case io.SeekCurrent:
 		r.index += offset
 	case io.SeekEnd:
-		return 0, ErrSeekEndNotSupported
+		var size int64
+		if size, err = r.s.Size(); err != nil {
+			return 0, err
+		}
+
+		r.index = size + offset
 	default:
 		return 0, ErrInvalidWhence
 	}
@@ -83,9 +200,164 @@ func (r *reader) Seek(offset int64, whence int) (pos int64, err error) {
 		err = ErrNegativeIndex
 	}
 
+	var low int64
+	if low = r.s.LowWaterMark(); r.index < low {
+		err = newOffsetEvictedError(r.index, low)
+		r.index = low
+	}
+
 	return r.index, err
 }
 
+// await waits like Read until fetch reports bytes at the reader offset and
+// returns the count once the bytes are confirmed to be retained and current.
+// The reader offset is not advanced.
+func (r *reader) await(ctx context.Context, fetch func(index int64) (n int, err error)) (n int, err error) {
+	for {
+		if err = r.checkCanceled(ctx); err != nil {
+			return 0, err
+		}
+
+		if err = r.checkRewind(); err != nil {
+			return 0, err
+		}
+
+		n, err = fetch(r.index)
+		switch {
+		case n > 0:
+			// Retention may discard bytes while they are being read, so the
+			// low-water mark is checked after the bytes are copied.
+			if err = r.s.checkRetained(r.index); err != nil {
+				return 0, err
+			}
+
+			// A followed file may be replaced during the read, in which case
+			// the copied bytes belong to the new file and are discarded.
+			if err = r.checkRewind(); err != nil {
+				return 0, err
+			}
+
+			return n, nil
+		case err == nil:
+		case r.s.isClosed() && r.tail:
+			return 0, io.EOF
+		case errors.Is(err, io.EOF) && r.tail:
+		case errors.Is(err, io.EOF):
+			// io.Copy and io.ReadAll require an unwrapped EOF.
+			return 0, io.EOF
+		default:
+			return 0, err
+		}
+
+		select {
+		case <-r.closer.Wait():
+			return 0, ErrIsClosed
+		case <-r.s.waiter.Wait():
+		case <-ctx.Done():
+		case <-r.deadline.Wait():
+		case <-r.disconnect.Wait():
+		}
+	}
+}
+
+// view returns up to n bytes from index, without copying when the backend
+// supports it.
+func (r *reader) view(index int64, n int) (out []byte, err error) {
+	if v, ok := r.s.r.(viewer); ok {
+		return v.viewAt(index, n)
+	}
+
+	out = make([]byte, n)
+	if n, err = r.s.r.ReadAt(out, index); n == 0 {
+		return nil, err
+	}
+
+	return out[:n], nil
+}
+
+// writeView writes the bytes currently available to w, waiting like Read when
+// none are. Bytes are viewed without copying unless buf is set.
+func (r *reader) writeView(w io.Writer, buf []byte) (written int64, err error) {
+	var out []byte
+	if buf == nil {
+		out, err = r.Next(maxWriteToRange)
+	} else {
+		var n int
+		n, err = r.Read(buf)
+		out = buf[:n]
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	if n, err = w.Write(out); err == nil && n < len(out) {
+		err = io.ErrShortWrite
+	}
+
+	return int64(n), err
+}
+
+// writeRange writes the bytes currently available to w through rw, waiting
+// like Read when none are.
+func (r *reader) writeRange(w io.Writer, rw rangeWriter) (written int64, err error) {
+	var n int
+	if n, err = r.await(context.Background(), func(index int64) (n int, err error) {
+		var size int64
+		if size, err = r.s.Size(); err != nil {
+			return 0, err
+		}
+
+		if index >= size {
+			return 0, io.EOF
+		}
+
+		return int(min(size-index, maxWriteToRange)), nil
+	}); err != nil {
+		return 0, err
+	}
+
+	index := r.index
+	written, err = rw.writeRangeTo(w, index, int64(n))
+	r.advance(int(written))
+	if err != nil {
+		return written, err
+	}
+
+	// Bytes evicted while they were written may have been sent as zeros.
+	return written, r.s.checkRetained(index)
+}
+
+// checkCanceled returns the error for a done ctx, a passed read deadline, or
+// a slow consumer disconnect.
+func (r *reader) checkCanceled(ctx context.Context) (err error) {
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	if isClosedChan(r.deadline.Wait()) {
+		return os.ErrDeadlineExceeded
+	}
+
+	if isClosedChan(r.disconnect.Wait()) {
+		return ErrSlowConsumer
+	}
+
+	return nil
+}
+
+// checkRewind restarts the reader from offset 0 and returns the cause when the
+// followed file was truncated or rotated since the reader last checked.
+func (r *reader) checkRewind() (err error) {
+	if r.generation, err = r.s.checkRewind(r.generation); err != nil {
+		r.setIndex(0)
+		return err
+	}
+
+	return nil
+}
+
 // Close closes the reader and unblocks any pending Read calls.
 // For tail readers, subsequent Read calls return ErrIsClosed when no bytes are read.
 func (r *reader) Close() (err error) {
@@ -93,6 +365,39 @@ func (r *reader) Close() (err error) {
 		return err
 	}
 
-	r.s.wg.Done()
+	r.s.readers.remove(r)
+	r.s.notifyAdvanced()
+	if !r.detached {
+		r.s.checkinReader()
+	}
+
 	return nil
 }
+
+// advance moves the reader past n read bytes and wakes writers held back by it.
+func (r *reader) advance(n int) {
+	if n == 0 {
+		return
+	}
+
+	r.setIndex(r.index + int64(n))
+	r.lastRead.Store(time.Now().UnixNano())
+	r.s.notifyAdvanced()
+}
+
+// setIndex moves the reader to index.
+func (r *reader) setIndex(index int64) {
+	r.index = index
+	r.position.Store(index)
+}
+
+// info returns a snapshot of the reader with lag measured against size.
+func (r *reader) info(name string, size int64) (out ReaderInfo) {
+	out.ID = r.id
+	out.Name = name
+	out.Streaming = r.tail
+	out.Offset = r.position.Load()
+	out.LastRead = time.Unix(0, r.lastRead.Load())
+	out.Lag = max(size-out.Offset, 0)
+	return out
+}
@@ -0,0 +1,19 @@
+package streambuf
+
+import "time"
+
+// ReaderInfo is a snapshot of a live reader returned by Readers.
+type ReaderInfo struct {
+	// ID identifies the reader for the lifetime of its stream.
+	ID uint64
+	// Name is the cursor name of a NamedReader and empty otherwise.
+	Name string
+	// Streaming reports whether the reader waits for future writes.
+	Streaming bool
+	// Offset is the absolute offset of the next byte the reader will read.
+	Offset int64
+	// LastRead is when the reader last returned bytes, or when it was opened.
+	LastRead time.Time
+	// Lag is the number of written bytes the reader has not yet read.
+	Lag int64
+}
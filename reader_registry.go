@@ -0,0 +1,90 @@
+package streambuf
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// newReaderRegistry constructs an empty registry.
+func newReaderRegistry() (out *readerRegistry) {
+	var rr readerRegistry
+	rr.readers = make(map[*reader]string)
+	return &rr
+}
+
+// readerRegistry tracks the live readers of a stream.
+type readerRegistry struct {
+	mux sync.Mutex
+
+	next uint64
+	// readers maps each live reader to its cursor name.
+	readers map[*reader]string
+}
+
+// add registers r and assigns its ID.
+func (rr *readerRegistry) add(r *reader) {
+	rr.mux.Lock()
+	defer rr.mux.Unlock()
+	rr.next++
+	r.id = rr.next
+	rr.readers[r] = ""
+}
+
+// setName records the cursor name of a registered reader.
+func (rr *readerRegistry) setName(r *reader, name string) {
+	rr.mux.Lock()
+	defer rr.mux.Unlock()
+	if _, ok := rr.readers[r]; ok {
+		rr.readers[r] = name
+	}
+}
+
+// remove forgets r. Removing an unregistered reader is a no-op.
+func (rr *readerRegistry) remove(r *reader) {
+	rr.mux.Lock()
+	defer rr.mux.Unlock()
+	delete(rr.readers, r)
+}
+
+// slowest returns the lowest offset of the readers that are not disconnected,
+// counting offsets below low as low since those bytes cannot be read.
+func (rr *readerRegistry) slowest(low int64) (offset int64, ok bool) {
+	rr.mux.Lock()
+	defer rr.mux.Unlock()
+	for r := range rr.readers {
+		if isClosedChan(r.disconnect.Wait()) {
+			continue
+		}
+
+		position := max(r.position.Load(), low)
+		if !ok || position < offset {
+			offset = position
+			ok = true
+		}
+	}
+
+	return offset, ok
+}
+
+// snapshot returns the info of every live reader ordered by ID, with lag
+// measured against size.
+func (rr *readerRegistry) snapshot(size int64) (out []ReaderInfo, readers []*reader) {
+	rr.mux.Lock()
+	defer rr.mux.Unlock()
+	readers = make([]*reader, 0, len(rr.readers))
+	for r := range rr.readers {
+		readers = append(readers, r)
+	}
+
+	slices.SortFunc(readers, func(a, b *reader) (n int) {
+		return cmp.Compare(a.id, b.id)
+	})
+
+	out = make([]ReaderInfo, 0, len(readers))
+	for _, r := range readers {
+		out = append(out, r.info(rr.readers[r], size))
+	}
+
+	return out, readers
+}
@@ -0,0 +1,263 @@
+package streambuf
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func Test_Buffer_Readers(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		// init opens readers on b and returns them for cleanup.
+		init func(t *testing.T, b *Buffer) (readers []io.Closer)
+
+		want []ReaderInfo
+	}
+
+	tests := []testcase{
+		{
+			name: "no readers",
+			init: func(t *testing.T, b *Buffer) (readers []io.Closer) {
+				return nil
+			},
+			want: []ReaderInfo{},
+		},
+		{
+			name: "offsets and lag",
+			init: func(t *testing.T, b *Buffer) (readers []io.Closer) {
+				r, err := b.Reader()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if _, err = r.Read(make([]byte, 4)); err != nil {
+					t.Fatal(err)
+				}
+
+				var sr io.ReadSeekCloser
+				if sr, err = b.StreamingReader(); err != nil {
+					t.Fatal(err)
+				}
+
+				return []io.Closer{r, sr}
+			},
+			want: []ReaderInfo{
+				{ID: 1, Offset: 4, Lag: 6},
+				{ID: 2, Streaming: true, Offset: 0, Lag: 10},
+			},
+		},
+		{
+			name: "closed readers are removed",
+			init: func(t *testing.T, b *Buffer) (readers []io.Closer) {
+				r, err := b.Reader()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				_ = r.Close()
+
+				var nr *NamedReader
+				if nr, err = b.NamedReader("parser"); err != nil {
+					t.Fatal(err)
+				}
+
+				if _, err = nr.Seek(10, io.SeekStart); err != nil {
+					t.Fatal(err)
+				}
+
+				return []io.Closer{nr}
+			},
+			want: []ReaderInfo{
+				{ID: 2, Name: "parser", Streaming: true, Offset: 10, Lag: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewMemory()
+			defer b.Close()
+			if _, err := b.Write([]byte("0123456789")); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, r := range tt.init(t, b) {
+				defer r.Close()
+			}
+
+			got, err := b.Readers()
+			if err != nil {
+				t.Fatalf("Readers() unexpected error: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Readers() invalid length, expected <%d> and received <%d>", len(tt.want), len(got))
+			}
+
+			for i, info := range got {
+				if info.LastRead.IsZero() {
+					t.Fatalf("Readers()[%d] invalid LastRead, expected non-zero time", i)
+				}
+
+				info.LastRead = time.Time{}
+				if info != tt.want[i] {
+					t.Fatalf("Readers()[%d] invalid value, expected <%+v> and received <%+v>", i, tt.want[i], info)
+				}
+			}
+		})
+	}
+}
+
+func Test_WithSlowConsumer(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		disconnect bool
+		write      int
+
+		wantSlow bool
+		wantErr  error
+	}
+
+	tests := []testcase{
+		{
+			name:     "within lag",
+			write:    4,
+			wantSlow: false,
+		},
+		{
+			name:     "callback only",
+			write:    16,
+			wantSlow: true,
+		},
+		{
+			name:       "disconnect",
+			disconnect: true,
+			write:      16,
+			wantSlow:   true,
+			wantErr:    ErrSlowConsumer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slow := make(chan ReaderInfo, 16)
+			var policy SlowConsumerPolicy
+			policy.MaxLag = 8
+			policy.Interval = 5 * time.Millisecond
+			policy.Disconnect = tt.disconnect
+			policy.OnSlow = func(info ReaderInfo) {
+				slow <- info
+			}
+
+			b := NewMemory(WithSlowConsumer(policy))
+			defer b.Close()
+
+			r, err := b.StreamingReader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			if _, err = b.Write(make([]byte, tt.write)); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case info := <-slow:
+				if !tt.wantSlow {
+					t.Fatalf("OnSlow() unexpected call: %+v", info)
+				}
+
+				if info.Lag != int64(tt.write) {
+					t.Fatalf("OnSlow() invalid lag, expected <%d> and received <%d>", tt.write, info.Lag)
+				}
+			case <-time.After(50 * time.Millisecond):
+				if tt.wantSlow {
+					t.Fatal("OnSlow() expected call")
+				}
+			}
+
+			_, err = r.Read(make([]byte, 1))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Read() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+			}
+
+			if !tt.disconnect {
+				return
+			}
+
+			// A disconnected reader is not reported again before it closes.
+			time.Sleep(25 * time.Millisecond)
+			if n := len(slow); n != 0 {
+				t.Fatalf("OnSlow() invalid calls after disconnect, expected <0> and received <%d>", n)
+			}
+		})
+	}
+}
+
+func Test_WithSlowConsumer_invalid_interval(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		open func(filepath string, opt Option) (err error)
+	}
+
+	tests := []testcase{
+		{
+			name: "New",
+			open: func(filepath string, opt Option) (err error) {
+				_, err = New(filepath, opt)
+				return err
+			},
+		},
+		{
+			name: "NewFileBackend",
+			open: func(filepath string, opt Option) (err error) {
+				_, _, err = NewFileBackend(filepath, opt)
+				return err
+			},
+		},
+		{
+			name: "NewStream",
+			open: func(filepath string, opt Option) (err error) {
+				_, err = NewStream(filepath, opt)
+				return err
+			},
+		},
+		{
+			name: "NewFollowStream",
+			open: func(filepath string, opt Option) (err error) {
+				_, err = NewFollowStream(filepath, opt)
+				return err
+			},
+		},
+		{
+			name: "NewStreamWithBackend",
+			open: func(filepath string, opt Option) (err error) {
+				_, err = NewStreamWithBackend(newReadableMemory(newMemory(nil)), opt)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var policy SlowConsumerPolicy
+			policy.MaxLag = 8
+
+			filepath := t.TempDir() + "/buffer.tmp"
+			if err := os.WriteFile(filepath, nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.open(filepath, WithSlowConsumer(policy)); !errors.Is(err, ErrInvalidInterval) {
+				t.Fatalf("%s() invalid error, expected <%v> and received <%v>", tt.name, ErrInvalidInterval, err)
+			}
+		})
+	}
+}
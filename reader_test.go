@@ -2,6 +2,8 @@ package streambuf
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"os"
 	"testing"
@@ -534,15 +536,60 @@ func Test_reader_Seek(t *testing.T) {
 			wantPos: 5,
 		},
 		{
-			name: "seek end not supported",
+			name: "memory seek end",
 			init: func(t *testing.T) (b *Buffer, err error) {
 				t.Helper()
-				return NewMemory(), nil
+
+				b = NewMemory()
+				if _, err = b.Write([]byte("hello world")); err != nil {
+					return nil, err
+				}
+
+				return b, nil
+			},
+			offset:  -5,
+			whence:  io.SeekEnd,
+			wantPos: 6,
+		},
+		{
+			name: "file seek end",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				t.Helper()
+
+				if b, err = New(t.TempDir() + "/reader-seek-end.tmp"); err != nil {
+					return nil, err
+				}
+
+				t.Cleanup(func() {
+					_ = b.Close()
+				})
+
+				if _, err = b.Write([]byte("hello world")); err != nil {
+					return nil, err
+				}
+
+				return b, nil
+			},
+			offset:  0,
+			whence:  io.SeekEnd,
+			wantPos: 11,
+		},
+		{
+			name: "seek end closed buffer",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				t.Helper()
+
+				b = NewMemory()
+				if err = b.Close(); err != nil {
+					return nil, err
+				}
+
+				return b, nil
 			},
 			offset:  0,
 			whence:  io.SeekEnd,
 			wantPos: 0,
-			wantErr: ErrSeekEndNotSupported,
+			wantErr: ErrIsClosed,
 		},
 		{
 			name: "invalid whence",
@@ -679,3 +726,336 @@ func Test_reader_Close(t *testing.T) {
 		})
 	}
 }
+
+func Test_reader_ReadContext(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		tail bool
+		ctx  func(t *testing.T) (ctx context.Context)
+
+		wantErr error
+	}
+
+	tests := []testcase{
+		{
+			name: "canceled before read",
+			tail: false,
+			ctx: func(t *testing.T) (ctx context.Context) {
+				t.Helper()
+				return expiredContext
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "deadline while waiting",
+			tail: true,
+			ctx: func(t *testing.T) (ctx context.Context) {
+				t.Helper()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				t.Cleanup(cancel)
+				return ctx
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				b      *Buffer
+				r      *reader
+				bs     []byte
+				gotN   int
+				gotErr error
+				err    error
+			)
+
+			b = NewMemory()
+			t.Cleanup(func() {
+				_ = b.Close()
+			})
+
+			if err = b.checkoutReader(); err != nil {
+				t.Fatal(err)
+			}
+
+			r = newReader(b.stream, tt.tail)
+			t.Cleanup(func() {
+				_ = r.Close()
+			})
+
+			bs = make([]byte, 5)
+			gotN, gotErr = r.ReadContext(tt.ctx(t), bs)
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Fatalf("ReadContext() invalid error, expected <%v> and received <%v>", tt.wantErr, gotErr)
+			}
+
+			if gotN != 0 {
+				t.Fatalf("ReadContext() invalid n, expected <0> and received <%v>", gotN)
+			}
+
+			// The reader keeps its offset and remains usable after a canceled read.
+			if _, err = b.Write([]byte("hello")); err != nil {
+				t.Fatalf("Write() unexpected error: %v", err)
+			}
+
+			if gotN, gotErr = r.ReadContext(context.Background(), bs); gotErr != nil {
+				t.Fatalf("ReadContext() unexpected error after cancellation: %v", gotErr)
+			}
+
+			if string(bs[:gotN]) != "hello" {
+				t.Fatalf("ReadContext() invalid value, expected <%v> and received <%v>", "hello", string(bs[:gotN]))
+			}
+		})
+	}
+}
+
+func Test_reader_SetReadDeadline(t *testing.T) {
+	type readResult struct {
+		n   int
+		err error
+	}
+
+	var (
+		b       *Buffer
+		r       *reader
+		results chan readResult
+		got     readResult
+		err     error
+	)
+
+	b = NewMemory()
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	if err = b.checkoutReader(); err != nil {
+		t.Fatal(err)
+	}
+
+	r = newReader(b.stream, true)
+	t.Cleanup(func() {
+		_ = r.Close()
+	})
+
+	results = make(chan readResult, 1)
+	go func() {
+		var out readResult
+		out.n, out.err = r.Read(make([]byte, 1))
+		results <- out
+	}()
+
+	// A deadline set while a read is pending unblocks that read.
+	time.Sleep(10 * time.Millisecond)
+	if err = r.SetReadDeadline(time.Now().Add(10 * time.Millisecond)); err != nil {
+		t.Fatalf("SetReadDeadline() unexpected error: %v", err)
+	}
+
+	select {
+	case got = <-results:
+	case <-time.After(time.Second):
+		t.Fatal("Read() did not unblock after deadline")
+	}
+
+	if !errors.Is(got.err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Read() invalid error, expected <%v> and received <%v>", os.ErrDeadlineExceeded, got.err)
+	}
+
+	if err = r.SetReadDeadline(time.Time{}); err != nil {
+		t.Fatalf("SetReadDeadline() unexpected error: %v", err)
+	}
+
+	if _, err = b.Write([]byte("a")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if got.n, got.err = r.Read(make([]byte, 1)); got.err != nil {
+		t.Fatalf("Read() unexpected error after clearing deadline: %v", got.err)
+	}
+
+	if got.n != 1 {
+		t.Fatalf("Read() invalid n after clearing deadline, expected <1> and received <%v>", got.n)
+	}
+}
+
+func Test_reader_WriteTo(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		init func(t *testing.T) (b *Buffer, err error)
+	}
+
+	tests := []testcase{
+		{
+			name: "memory",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				return NewMemory(), nil
+			},
+		},
+		{
+			name: "chunks",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				return NewMemory(WithChunkSize(4)), nil
+			},
+		},
+		{
+			name: "file",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				return New(t.TempDir() + "/buffer.tmp")
+			},
+		},
+		{
+			name: "mmap",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				return New(t.TempDir()+"/buffer.tmp", WithMmap())
+			},
+		},
+		{
+			name: "segments",
+			init: func(t *testing.T) (b *Buffer, err error) {
+				return NewSegmented(t.TempDir(), SegmentConfig{MaxBytes: 4})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.init(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer b.Close()
+
+			if _, err = b.Write([]byte("hello world")); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := b.Reader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			if _, err = r.Seek(6, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+
+			var got bytes.Buffer
+			n, err := io.Copy(&got, r)
+			if err != nil {
+				t.Fatalf("WriteTo() unexpected error: %v", err)
+			}
+
+			if n != 5 || got.String() != "world" {
+				t.Fatalf("WriteTo() invalid value, expected <world> and received <%s> (%d)", got.String(), n)
+			}
+
+			sr, err := b.StreamingReader()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sr.Close()
+
+			var streamed bytes.Buffer
+			done := make(chan error, 1)
+			go func() {
+				_, err := sr.(io.WriterTo).WriteTo(&streamed)
+				done <- err
+			}()
+
+			if _, err = b.Write([]byte("!")); err != nil {
+				t.Fatal(err)
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			if err = b.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err = <-done; err != nil {
+				t.Fatalf("WriteTo() unexpected error: %v", err)
+			}
+
+			if streamed.String() != "hello world!" {
+				t.Fatalf("WriteTo() invalid value, expected <hello world!> and received <%s>", streamed.String())
+			}
+		})
+	}
+}
+
+func Test_reader_Peek(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		init func(t *testing.T) (b *Buffer, err error)
+
+		// wantView reports whether Peek returns bytes without copying.
+		wantView bool
+	}
+
+	tests := []testcase{
+		{
+			name:     "memory",
+			init:     func(t *testing.T) (b *Buffer, err error) { return NewMemory(), nil },
+			wantView: true,
+		},
+		{
+			name:     "chunks",
+			init:     func(t *testing.T) (b *Buffer, err error) { return NewMemory(WithChunkSize(64)), nil },
+			wantView: true,
+		},
+		{
+			name: "file",
+			init: func(t *testing.T) (b *Buffer, err error) { return New(t.TempDir() + "/buffer.tmp") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.init(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer b.Close()
+
+			if _, err = b.Write([]byte("hello world")); err != nil {
+				t.Fatal(err)
+			}
+
+			rd, err := b.openReader(false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rd.Close()
+
+			first, err := rd.Peek(5)
+			if err != nil || string(first) != "hello" {
+				t.Fatalf("Peek() invalid result, expected <hello> and received <%s> <%v>", first, err)
+			}
+
+			if cap(first) != len(first) {
+				t.Fatalf("Peek() invalid capacity, expected <%d> and received <%d>", len(first), cap(first))
+			}
+
+			again, err := rd.Next(5)
+			if err != nil || string(again) != "hello" {
+				t.Fatalf("Next() invalid result, expected <hello> and received <%s> <%v>", again, err)
+			}
+
+			if gotView := &first[0] == &again[0]; gotView != tt.wantView {
+				t.Fatalf("Peek() invalid aliasing, expected <%v> and received <%v>", tt.wantView, gotView)
+			}
+
+			rest, err := rd.Next(64)
+			if err != nil || string(rest) != " world" {
+				t.Fatalf("Next() invalid result, expected < world> and received <%s> <%v>", rest, err)
+			}
+
+			if _, err = rd.Peek(1); !errors.Is(err, io.EOF) {
+				t.Fatalf("Peek() invalid error, expected <%v> and received <%v>", io.EOF, err)
+			}
+		})
+	}
+}
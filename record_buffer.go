@@ -0,0 +1,90 @@
+package streambuf
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+)
+
+const recordChecksumSize = 4
+
+// MaxRecordSize is the largest record payload, in bytes, that a RecordBuffer
+// writes or a RecordReader accepts.
+const MaxRecordSize = 64 << 20
+
+var recordTable = crc32.MakeTable(crc32.Castagnoli)
+
+// NewRecordBuffer wraps b with length-prefixed record framing.
+// When checksum is true, every record carries a CRC-32C of its payload that
+// readers verify. Readers must be created from a RecordBuffer with the same
+// checksum setting that wrote the records.
+func NewRecordBuffer(b *Buffer, checksum bool) (out *RecordBuffer) {
+	var rb RecordBuffer
+	rb.b = b
+	rb.checksum = checksum
+	return &rb
+}
+
+// RecordBuffer frames each write as a record so readers observe whole messages.
+// Each record is a uvarint payload length, an optional big-endian CRC-32C,
+// and the payload.
+type RecordBuffer struct {
+	b *Buffer
+
+	checksum bool
+}
+
+// WriteRecord appends rec as a single framed record.
+// The frame is written with one Buffer.Write call, so concurrent records never interleave.
+// It returns ErrRecordTooLarge if rec exceeds MaxRecordSize.
+func (rb *RecordBuffer) WriteRecord(rec []byte) (err error) {
+	if len(rec) > MaxRecordSize {
+		return ErrRecordTooLarge
+	}
+
+	_, err = rb.b.Write(rb.frame(rec))
+	return err
+}
+
+// Reader returns a RecordReader that returns io.EOF once no complete record remains.
+// It returns ErrIsClosed if the buffer is closed.
+func (rb *RecordBuffer) Reader() (r *RecordReader, err error) {
+	var br *reader
+	if br, err = rb.b.openReader(false); err != nil {
+		return nil, err
+	}
+
+	return newRecordReader(br, rb.checksum), nil
+}
+
+// StreamingReader returns a RecordReader that waits for future records when
+// the current end is reached. It returns ErrIsClosed if the buffer is closed.
+func (rb *RecordBuffer) StreamingReader() (r *RecordReader, err error) {
+	var br *reader
+	if br, err = rb.b.openReader(true); err != nil {
+		return nil, err
+	}
+
+	return newRecordReader(br, rb.checksum), nil
+}
+
+// Close closes the underlying Buffer.
+func (rb *RecordBuffer) Close() (err error) {
+	return rb.b.Close()
+}
+
+// CloseAndWait closes the underlying Buffer and waits for readers until ctx is canceled.
+func (rb *RecordBuffer) CloseAndWait(ctx context.Context) (err error) {
+	return rb.b.CloseAndWait(ctx)
+}
+
+// frame returns rec encoded with its record header.
+func (rb *RecordBuffer) frame(rec []byte) (out []byte) {
+	out = make([]byte, 0, binary.MaxVarintLen64+recordChecksumSize+len(rec))
+	out = binary.AppendUvarint(out, uint64(len(rec)))
+	if rb.checksum {
+		out = binary.BigEndian.AppendUint32(out, crc32.Checksum(rec, recordTable))
+	}
+
+	return append(out, rec...)
+}
@@ -0,0 +1,316 @@
+package streambuf
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func Test_RecordBuffer_WriteRecord(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		checksum bool
+		records  [][]byte
+	}
+
+	large := bytes.Repeat([]byte("x"), 300)
+	tests := []testcase{
+		{
+			name:    "without checksum",
+			records: [][]byte{[]byte("hello"), {}, large},
+		},
+		{
+			name:     "with checksum",
+			checksum: true,
+			records:  [][]byte{[]byte("hello"), {}, large},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				rb      *RecordBuffer
+				r       *RecordReader
+				got     []byte
+				offsets []int64
+				err     error
+			)
+
+			rb = NewRecordBuffer(NewMemory(), tt.checksum)
+			t.Cleanup(func() {
+				_ = rb.Close()
+			})
+
+			for _, rec := range tt.records {
+				if err = rb.WriteRecord(rec); err != nil {
+					t.Fatalf("WriteRecord() unexpected error: %v", err)
+				}
+			}
+
+			if r, err = rb.Reader(); err != nil {
+				t.Fatalf("Reader() unexpected error: %v", err)
+			}
+
+			t.Cleanup(func() {
+				_ = r.Close()
+			})
+
+			for _, want := range tt.records {
+				offsets = append(offsets, r.Offset())
+				if got, err = r.ReadRecord(); err != nil {
+					t.Fatalf("ReadRecord() unexpected error: %v", err)
+				}
+
+				if !bytes.Equal(got, want) {
+					t.Fatalf("ReadRecord() invalid value, expected <%v> and received <%v>", string(want), string(got))
+				}
+			}
+
+			if _, err = r.ReadRecord(); err != io.EOF {
+				t.Fatalf("ReadRecord() invalid error at end, expected <%v> and received <%v>", io.EOF, err)
+			}
+
+			// Seeking to a previously observed boundary replays from that record.
+			if _, err = r.Seek(offsets[2], io.SeekStart); err != nil {
+				t.Fatalf("Seek() unexpected error: %v", err)
+			}
+
+			if got, err = r.ReadRecord(); err != nil {
+				t.Fatalf("ReadRecord() unexpected error after Seek(): %v", err)
+			}
+
+			if !bytes.Equal(got, large) {
+				t.Fatalf("ReadRecord() invalid value after Seek(), expected <%d bytes> and received <%d bytes>", len(large), len(got))
+			}
+		})
+	}
+}
+
+func Test_RecordReader_ReadRecord_partial_record(t *testing.T) {
+	var (
+		rb    *RecordBuffer
+		r     *RecordReader
+		frame []byte
+		got   []byte
+		err   error
+	)
+
+	rb = NewRecordBuffer(NewMemory(), true)
+	t.Cleanup(func() {
+		_ = rb.Close()
+	})
+
+	if r, err = rb.Reader(); err != nil {
+		t.Fatalf("Reader() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = r.Close()
+	})
+
+	frame = rb.frame([]byte("hello world"))
+	if _, err = rb.b.Write(frame[:7]); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if _, err = r.ReadRecord(); err != io.EOF {
+		t.Fatalf("ReadRecord() invalid error for partial record, expected <%v> and received <%v>", io.EOF, err)
+	}
+
+	if r.Offset() != 0 {
+		t.Fatalf("Offset() invalid value after partial record, expected <0> and received <%v>", r.Offset())
+	}
+
+	if _, err = rb.b.Write(frame[7:]); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if got, err = r.ReadRecord(); err != nil {
+		t.Fatalf("ReadRecord() unexpected error after completing record: %v", err)
+	}
+
+	if string(got) != "hello world" {
+		t.Fatalf("ReadRecord() invalid value, expected <%v> and received <%v>", "hello world", string(got))
+	}
+
+	if r.Offset() != int64(len(frame)) {
+		t.Fatalf("Offset() invalid value, expected <%v> and received <%v>", len(frame), r.Offset())
+	}
+}
+
+func Test_RecordReader_ReadRecord_corrupt_record(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		checksum bool
+		init     func(t *testing.T, rb *RecordBuffer)
+
+		wantNext    string
+		wantNextErr error
+		wantOffset  int64
+	}
+
+	tests := []testcase{
+		{
+			name:     "checksum mismatch is skipped",
+			checksum: true,
+			init: func(t *testing.T, rb *RecordBuffer) {
+				frame := rb.frame([]byte("hello"))
+				frame[len(frame)-1] = 'X'
+				if _, err := rb.b.Write(frame); err != nil {
+					t.Fatalf("Write() unexpected error: %v", err)
+				}
+
+				if err := rb.WriteRecord([]byte("world")); err != nil {
+					t.Fatalf("WriteRecord() unexpected error: %v", err)
+				}
+			},
+			wantNext:   "world",
+			wantOffset: 20,
+		},
+		{
+			name: "oversized header is not skipped",
+			init: func(t *testing.T, rb *RecordBuffer) {
+				if _, err := rb.b.Write([]byte("\xff\xff\xff\xff\xff\xff\xff\xff\x7f")); err != nil {
+					t.Fatalf("Write() unexpected error: %v", err)
+				}
+			},
+			wantNextErr: ErrCorruptRecord,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				rb  *RecordBuffer
+				r   *RecordReader
+				got []byte
+				err error
+			)
+
+			rb = NewRecordBuffer(NewMemory(), tt.checksum)
+			t.Cleanup(func() {
+				_ = rb.Close()
+			})
+
+			tt.init(t, rb)
+			if r, err = rb.Reader(); err != nil {
+				t.Fatalf("Reader() unexpected error: %v", err)
+			}
+
+			t.Cleanup(func() {
+				_ = r.Close()
+			})
+
+			if _, err = r.ReadRecord(); !errors.Is(err, ErrCorruptRecord) {
+				t.Fatalf("ReadRecord() invalid error, expected <%v> and received <%v>", ErrCorruptRecord, err)
+			}
+
+			got, err = r.ReadRecord()
+			if !isEqualErrors(err, tt.wantNextErr) {
+				t.Fatalf("ReadRecord() invalid error after corrupt record, expected <%v> and received <%v>", tt.wantNextErr, err)
+			}
+
+			if string(got) != tt.wantNext {
+				t.Fatalf("ReadRecord() invalid value, expected <%v> and received <%v>", tt.wantNext, string(got))
+			}
+
+			if r.Offset() != tt.wantOffset {
+				t.Fatalf("Offset() invalid value, expected <%v> and received <%v>", tt.wantOffset, r.Offset())
+			}
+		})
+	}
+}
+
+func Test_RecordBuffer_WriteRecord_too_large(t *testing.T) {
+	var (
+		rb   *RecordBuffer
+		size int64
+		err  error
+	)
+
+	rb = NewRecordBuffer(NewMemory(), false)
+	t.Cleanup(func() {
+		_ = rb.Close()
+	})
+
+	if err = rb.WriteRecord(make([]byte, MaxRecordSize+1)); !errors.Is(err, ErrRecordTooLarge) {
+		t.Fatalf("WriteRecord() invalid error, expected <%v> and received <%v>", ErrRecordTooLarge, err)
+	}
+
+	if size, err = rb.b.Size(); err != nil {
+		t.Fatalf("Size() unexpected error: %v", err)
+	}
+
+	if size != 0 {
+		t.Fatalf("Size() invalid value, expected <%v> and received <%v>", 0, size)
+	}
+}
+
+func Test_RecordBuffer_StreamingReader(t *testing.T) {
+	type readResult struct {
+		rec []byte
+		err error
+	}
+
+	var (
+		rb      *RecordBuffer
+		r       *RecordReader
+		frame   []byte
+		results chan readResult
+		got     readResult
+		err     error
+	)
+
+	rb = NewRecordBuffer(NewMemory(), false)
+	t.Cleanup(func() {
+		_ = rb.Close()
+	})
+
+	if r, err = rb.StreamingReader(); err != nil {
+		t.Fatalf("StreamingReader() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = r.Close()
+	})
+
+	results = make(chan readResult, 1)
+	go func() {
+		var out readResult
+		out.rec, out.err = r.ReadRecord()
+		results <- out
+	}()
+
+	frame = rb.frame([]byte("hello world"))
+	if _, err = rb.b.Write(frame[:4]); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	select {
+	case got = <-results:
+		t.Fatalf("ReadRecord() returned a partial record, rec=<%v> err=<%v>", string(got.rec), got.err)
+	case <-time.After(25 * time.Millisecond):
+	}
+
+	if _, err = rb.b.Write(frame[4:]); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	select {
+	case got = <-results:
+	case <-time.After(time.Second):
+		t.Fatal("ReadRecord() did not unblock after record completed")
+	}
+
+	if got.err != nil {
+		t.Fatalf("ReadRecord() unexpected error: %v", got.err)
+	}
+
+	if string(got.rec) != "hello world" {
+		t.Fatalf("ReadRecord() invalid value, expected <%v> and received <%v>", "hello world", string(got.rec))
+	}
+}
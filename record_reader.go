@@ -0,0 +1,167 @@
+package streambuf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+// newRecordReader constructs a RecordReader over a byte reader positioned at a record boundary.
+func newRecordReader(r *reader, checksum bool) (out *RecordReader) {
+	var rr RecordReader
+	rr.r = r
+	rr.br = bufio.NewReader(r)
+	rr.checksum = checksum
+	rr.offset = r.index
+	return &rr
+}
+
+// RecordReader reads whole records from a RecordBuffer while tracking its own
+// record boundary. A partially written or interrupted record is never returned;
+// the reader rewinds to the record boundary instead.
+type RecordReader struct {
+	r  *reader
+	br *bufio.Reader
+
+	checksum bool
+	offset   int64
+}
+
+// ReadRecord returns the next record payload.
+// Non-streaming readers return io.EOF when no complete record remains.
+// A record with an invalid checksum returns ErrCorruptRecord and is skipped.
+// A header declaring more than MaxRecordSize bytes also returns
+// ErrCorruptRecord, but the reader cannot skip past it and stays at the
+// record boundary.
+// Any other error rewinds the reader to the start of the record.
+func (rr *RecordReader) ReadRecord() (rec []byte, err error) {
+	var size int64
+	rec, size, err = rr.readRecord()
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrCorruptRecord) && size == 0:
+		return nil, rr.rewind(err)
+	case errors.Is(err, ErrCorruptRecord):
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return nil, rr.rewind(io.EOF)
+	default:
+		return nil, rr.rewind(err)
+	}
+
+	rr.offset += size
+	return rec, err
+}
+
+// Offset returns the absolute offset of the next record boundary.
+func (rr *RecordReader) Offset() (offset int64) {
+	return rr.offset
+}
+
+// Seek moves the reader using whence semantics. The resulting position must
+// be a record boundary, such as one previously returned by Offset.
+func (rr *RecordReader) Seek(offset int64, whence int) (pos int64, err error) {
+	pos, err = rr.r.Seek(offset, whence)
+	rr.offset = pos
+	rr.br.Reset(rr.r)
+	return pos, err
+}
+
+// SetReadDeadline bounds ReadRecord as ContextReader describes.
+func (rr *RecordReader) SetReadDeadline(t time.Time) (err error) {
+	return rr.r.SetReadDeadline(t)
+}
+
+// Close closes the reader and unblocks any pending ReadRecord calls.
+func (rr *RecordReader) Close() (err error) {
+	return rr.r.Close()
+}
+
+// readRecord decodes a single record and returns the number of bytes it spans.
+func (rr *RecordReader) readRecord() (rec []byte, size int64, err error) {
+	var length uint64
+	if length, err = binary.ReadUvarint(rr.br); err != nil {
+		return nil, 0, err
+	}
+
+	var sum [recordChecksumSize]byte
+	if rr.checksum {
+		if _, err = io.ReadFull(rr.br, sum[:]); err != nil {
+			return nil, 0, unexpectedEOF(err)
+		}
+	}
+
+	if length > MaxRecordSize {
+		return nil, 0, ErrCorruptRecord
+	}
+
+	size = int64(uvarintLen(length)) + int64(length)
+	if rr.checksum {
+		size += recordChecksumSize
+	}
+
+	if rec, err = rr.readPayload(int64(length), size); err != nil {
+		return nil, 0, unexpectedEOF(err)
+	}
+
+	if !rr.checksum {
+		return rec, size, nil
+	}
+
+	if binary.BigEndian.Uint32(sum[:]) != crc32.Checksum(rec, recordTable) {
+		return nil, size, ErrCorruptRecord
+	}
+
+	return rec, size, nil
+}
+
+// readPayload reads a payload of length bytes for a record spanning size bytes.
+// The payload is only allocated up front once the whole record is visible, so
+// a header declaring more bytes than exist cannot force a large allocation.
+// Otherwise the payload grows as bytes arrive.
+func (rr *RecordReader) readPayload(length, size int64) (rec []byte, err error) {
+	var end int64
+	if end, err = rr.r.s.Size(); err == nil && rr.offset+size <= end {
+		rec = make([]byte, length)
+		_, err = io.ReadFull(rr.br, rec)
+		return rec, err
+	}
+
+	var (
+		buf bytes.Buffer
+		n   int64
+	)
+
+	if n, err = io.CopyN(&buf, rr.br, length); n == length {
+		err = nil
+	}
+
+	return buf.Bytes(), err
+}
+
+// rewind moves the reader back to the current record boundary and returns cause.
+func (rr *RecordReader) rewind(cause error) (err error) {
+	if _, err = rr.Seek(rr.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	return cause
+}
+
+// unexpectedEOF converts an EOF within a record into io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) (out error) {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// uvarintLen returns the encoded size of v as a uvarint.
+func uvarintLen(v uint64) (n int) {
+	var bs [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(bs[:], v)
+}
@@ -0,0 +1,152 @@
+package streambuf
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// newRecovery validates the data file at filepath against its journal.
+// When adopt is true, an unjournaled file is accepted whole, which allows files
+// created by New to be reopened with Open.
+func newRecovery(filepath string, j *journal, mode RecoveryMode, adopt bool, perm os.FileMode) (rec Recovery, err error) {
+	var f *os.File
+	if f, err = os.OpenFile(filepath, os.O_RDWR|os.O_CREATE, perm); err != nil {
+		return rec, fmt.Errorf("open recovery file: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		info       os.FileInfo
+		checkpoint journalCheckpoint
+		entries    []journalEntry
+		valid      []journalEntry
+	)
+
+	if info, err = f.Stat(); err != nil {
+		return rec, fmt.Errorf("stat recovery file: %w", err)
+	}
+
+	if checkpoint, err = j.checkpointed(); err != nil {
+		return rec, err
+	}
+
+	// A checkpoint beyond the file means the file was replaced or cut short,
+	// so nothing it confirmed can be trusted.
+	if checkpoint.length > info.Size() {
+		checkpoint = journalCheckpoint{}
+	}
+
+	j.low = checkpoint.low
+	rec.LowWaterMark = checkpoint.low
+
+	if entries, err = j.entries(); err != nil {
+		return rec, err
+	}
+
+	if adopt && len(entries) == 0 && info.Size() > 0 {
+		return adoptFile(f, j, info.Size())
+	}
+
+	if rec.Length, valid, err = validEntries(f, entries, checkpoint.length, info.Size()); err != nil {
+		return rec, err
+	}
+
+	rec.Truncated = info.Size() - rec.Length
+	if rec.Truncated == 0 && len(valid) == len(entries) {
+		return rec, compactRecovered(f, j, rec.Length)
+	}
+
+	if mode != RecoverTruncate {
+		return rec, ErrTornTail
+	}
+
+	if err = f.Truncate(rec.Length); err != nil {
+		return rec, fmt.Errorf("truncate recovery file: %w", err)
+	}
+
+	if err = j.reset(valid); err != nil {
+		return rec, err
+	}
+
+	return rec, compactRecovered(f, j, rec.Length)
+}
+
+// Recovery reports the outcome of validating a file when it is opened.
+type Recovery struct {
+	// Length is the number of bytes confirmed by the journal.
+	Length int64
+	// Truncated is the number of torn bytes found after Length.
+	Truncated int64
+	// LowWaterMark is the low-water mark persisted by Truncate. Bytes before
+	// it may have been reclaimed and are not validated.
+	LowWaterMark int64
+}
+
+// adoptFile checkpoints an existing unjournaled file whole.
+func adoptFile(f *os.File, j *journal, size int64) (rec Recovery, err error) {
+	if err = f.Sync(); err != nil {
+		return rec, fmt.Errorf("sync recovery file: %w", err)
+	}
+
+	rec.Length = size
+	rec.LowWaterMark = j.low
+	return rec, j.compact(size)
+}
+
+// compactRecovered checkpoints a recovered length once the journal holds
+// enough entries, so later opens do not checksum them again.
+func compactRecovered(f *os.File, j *journal, length int64) (err error) {
+	if j.count < journalCompactEntries {
+		return nil
+	}
+
+	if err = f.Sync(); err != nil {
+		return fmt.Errorf("sync recovery file: %w", err)
+	}
+
+	return j.compact(length)
+}
+
+// validEntries returns the entries after start whose checksums match the data
+// file, along with the length they cover. Entries at or before the covered
+// length were already confirmed by a checkpoint and are skipped. A checkpoint
+// always covers its low-water mark, so bytes reclaimed by Truncate are never
+// checksummed.
+func validEntries(f *os.File, entries []journalEntry, start, size int64) (length int64, valid []journalEntry, err error) {
+	var crc uint32
+	length = start
+	for _, e := range entries {
+		if e.end <= length {
+			continue
+		}
+
+		if e.end > size {
+			break
+		}
+
+		if crc, err = checksumRange(f, length, e.end); err != nil {
+			return 0, nil, err
+		}
+
+		if crc != e.crc {
+			break
+		}
+
+		length = e.end
+		valid = append(valid, e)
+	}
+
+	return length, valid, nil
+}
+
+// checksumRange returns the journal checksum of the bytes in [start, end) of f.
+func checksumRange(f *os.File, start, end int64) (crc uint32, err error) {
+	h := crc32.New(journalTable)
+	if _, err = io.Copy(h, io.NewSectionReader(f, start, end-start)); err != nil {
+		return 0, fmt.Errorf("read recovery file at index %d: %w", start, err)
+	}
+
+	return h.Sum32(), nil
+}
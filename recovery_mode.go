@@ -0,0 +1,11 @@
+package streambuf
+
+// RecoveryMode controls how Open handles a torn tail left behind by a crash.
+type RecoveryMode int
+
+const (
+	// RecoverReport leaves a torn tail in place and causes Open to return ErrTornTail.
+	RecoverReport RecoveryMode = iota
+	// RecoverTruncate truncates a torn tail so appending can resume safely.
+	RecoverTruncate
+)
@@ -0,0 +1,90 @@
+package streambuf
+
+import (
+	"time"
+)
+
+// maxRetentionMarks bounds the marks kept for RetentionInfo.OffsetAt.
+const maxRetentionMarks = 1 << 10
+
+// newRetention constructs a retention runner that applies policy to b every interval.
+func newRetention(b *Buffer, policy RetentionPolicy, interval time.Duration) (out *retention) {
+	var r retention
+	r.b = b
+	r.policy = policy
+	r.p = newPeriodic("retention", interval, b.c.logger, r.evaluate)
+	return &r
+}
+
+// retention periodically evaluates a RetentionPolicy and truncates its Buffer.
+type retention struct {
+	b *Buffer
+
+	policy RetentionPolicy
+
+	marks []retentionMark
+
+	p *periodic
+}
+
+// evaluate records the current size and truncates the buffer to the policy low-water mark.
+func (r *retention) evaluate(now time.Time) (err error) {
+	var info RetentionInfo
+	if info.Size, err = r.b.Size(); err != nil {
+		return err
+	}
+
+	r.record(now, info.Size)
+	info.Now = now
+	info.LowWaterMark = r.b.LowWaterMark()
+	info.marks = r.marks
+
+	if err = r.b.Truncate(r.policy.LowWaterMark(info)); err != nil {
+		return err
+	}
+
+	r.prune(r.b.LowWaterMark())
+	return nil
+}
+
+// record appends a mark for size at now. A size equal to the latest mark is
+// already described by it, so no mark is added. Once maxRetentionMarks is
+// reached, every other mark is dropped, so OffsetAt loses resolution rather
+// than history and still never returns bytes written after its argument.
+func (r *retention) record(now time.Time, size int64) {
+	if n := len(r.marks); n > 0 && r.marks[n-1].size == size {
+		return
+	}
+
+	if len(r.marks) >= maxRetentionMarks {
+		r.thin()
+	}
+
+	r.marks = append(r.marks, retentionMark{at: now, size: size})
+}
+
+// thin drops every other mark, keeping the oldest.
+func (r *retention) thin() {
+	kept := r.marks[:0]
+	for i := 0; i < len(r.marks); i += 2 {
+		kept = append(kept, r.marks[i])
+	}
+
+	clear(r.marks[len(kept):])
+	r.marks = kept
+}
+
+// prune drops marks that no longer describe readable bytes.
+func (r *retention) prune(low int64) {
+	i := 0
+	for i < len(r.marks)-1 && r.marks[i].size <= low {
+		i++
+	}
+
+	r.marks = r.marks[i:]
+}
+
+// Close stops the retention runner without waiting for an in-flight evaluation.
+func (r *retention) Close() (err error) {
+	return r.p.Close()
+}
@@ -0,0 +1,28 @@
+package streambuf
+
+import "time"
+
+var _ RetentionPolicy = RetentionFunc(nil)
+
+// MaxBytes returns a RetentionPolicy that keeps at most n of the newest bytes.
+func MaxBytes(n int64) (out RetentionFunc) {
+	return func(info RetentionInfo) (offset int64) {
+		return info.Size - n
+	}
+}
+
+// MaxAge returns a RetentionPolicy that discards bytes written more than d ago.
+// Age is tracked at the retention interval, so bytes may outlive d by up to one interval.
+func MaxAge(d time.Duration) (out RetentionFunc) {
+	return func(info RetentionInfo) (offset int64) {
+		return info.OffsetAt(info.Now.Add(-d))
+	}
+}
+
+// RetentionFunc adapts a function into a RetentionPolicy for custom predicates.
+type RetentionFunc func(info RetentionInfo) (offset int64)
+
+// LowWaterMark calls fn(info).
+func (fn RetentionFunc) LowWaterMark(info RetentionInfo) (offset int64) {
+	return fn(info)
+}
@@ -0,0 +1,29 @@
+package streambuf
+
+import "time"
+
+// RetentionInfo describes a Buffer when a RetentionPolicy is evaluated.
+type RetentionInfo struct {
+	// Now is the time of the evaluation.
+	Now time.Time
+	// LowWaterMark is the oldest offset that is currently readable.
+	LowWaterMark int64
+	// Size is the offset immediately after the newest byte.
+	Size int64
+
+	marks []retentionMark
+}
+
+// OffsetAt returns the size the Buffer had at the latest retention interval at
+// or before t. Every byte before the returned offset was written before t.
+func (i RetentionInfo) OffsetAt(t time.Time) (offset int64) {
+	for _, m := range i.marks {
+		if m.at.After(t) {
+			break
+		}
+
+		offset = m.size
+	}
+
+	return offset
+}
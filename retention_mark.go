@@ -0,0 +1,9 @@
+package streambuf
+
+import "time"
+
+// retentionMark records the size of a Buffer at a point in time.
+type retentionMark struct {
+	at   time.Time
+	size int64
+}
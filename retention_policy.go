@@ -0,0 +1,8 @@
+package streambuf
+
+// RetentionPolicy decides which head of a Buffer may be discarded.
+// LowWaterMark returns the lowest offset that must be kept; bytes before it
+// are dropped and become unreadable.
+type RetentionPolicy interface {
+	LowWaterMark(info RetentionInfo) (offset int64)
+}
@@ -0,0 +1,73 @@
+package streambuf
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_retention_record(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		sizes []int64
+
+		wantMarks int
+		wantAt    int64
+	}
+
+	tests := []testcase{
+		{
+			name:      "distinct sizes",
+			sizes:     []int64{1, 2, 3},
+			wantMarks: 3,
+			wantAt:    3,
+		},
+		{
+			name:      "repeated sizes",
+			sizes:     []int64{1, 1, 1, 2, 2},
+			wantMarks: 2,
+			wantAt:    1,
+		},
+		{
+			name:      "capped",
+			sizes:     countTo(maxRetentionMarks + 1),
+			wantMarks: maxRetentionMarks/2 + 1,
+			wantAt:    3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				r    retention
+				info RetentionInfo
+			)
+
+			start := time.Unix(0, 0)
+			for i, size := range tt.sizes {
+				r.record(start.Add(time.Duration(i)*time.Second), size)
+				if len(r.marks) > maxRetentionMarks {
+					t.Fatalf("record() invalid mark count, expected at most <%v> and received <%v>", maxRetentionMarks, len(r.marks))
+				}
+			}
+
+			if len(r.marks) != tt.wantMarks {
+				t.Fatalf("record() invalid mark count, expected <%v> and received <%v>", tt.wantMarks, len(r.marks))
+			}
+
+			info.marks = r.marks
+			if got := info.OffsetAt(start.Add(2 * time.Second)); got != tt.wantAt {
+				t.Fatalf("OffsetAt() invalid value, expected <%v> and received <%v>", tt.wantAt, got)
+			}
+		})
+	}
+}
+
+// countTo returns the sizes 1 through n.
+func countTo(n int) (out []int64) {
+	for i := 1; i <= n; i++ {
+		out = append(out, int64(i))
+	}
+
+	return out
+}
@@ -0,0 +1,8 @@
+package streambuf
+
+// rewind records that a followed file was truncated or replaced, so readers
+// restart from offset 0.
+type rewind struct {
+	generation uint64
+	cause      error
+}
@@ -0,0 +1,155 @@
+package streambuf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	segmentExt = ".seg"
+	// segmentCreatedExt names the sidecar that records when a segment was
+	// created, so SegmentConfig.MaxAge survives restarts.
+	segmentCreatedExt = ".created"
+)
+
+// newSegment opens a read handle for the segment file starting at base within
+// dir, creating the file and its creation record when it does not exist.
+func newSegment(dir string, base int64, perm os.FileMode) (out *segment, err error) {
+	var (
+		s    segment
+		info os.FileInfo
+	)
+
+	s.base = base
+	s.path = filepath.Join(dir, segmentName(base))
+	if s.f, err = os.OpenFile(s.path, os.O_RDONLY|os.O_CREATE|os.O_EXCL, perm); err == nil {
+		s.created = time.Now()
+		if err = writeSegmentCreated(s.path, s.created, perm); err != nil {
+			_ = s.f.Close()
+			return nil, err
+		}
+
+		return &s, nil
+	}
+
+	if s.f, err = os.Open(s.path); err != nil {
+		return nil, fmt.Errorf("open segment file: %w", err)
+	}
+
+	if info, err = s.f.Stat(); err != nil {
+		_ = s.f.Close()
+		return nil, fmt.Errorf("stat segment file: %w", err)
+	}
+
+	s.size = info.Size()
+	s.created = readSegmentCreated(s.path, info.ModTime())
+	return &s, nil
+}
+
+// segment is a single numbered file within a segmented backend.
+// Its name is the absolute offset of its first byte.
+type segment struct {
+	base    int64
+	size    int64
+	created time.Time
+
+	path string
+	f    *os.File
+}
+
+// end returns the absolute offset immediately after the last byte of the segment.
+func (s *segment) end() (offset int64) {
+	return s.base + s.size
+}
+
+// readAt copies bytes from the absolute index into in without reading past size.
+func (s *segment) readAt(in []byte, index int64) (n int, err error) {
+	remaining := s.end() - index
+	if int64(len(in)) > remaining {
+		in = in[:remaining]
+	}
+
+	if n, err = s.f.ReadAt(in, index-s.base); n > 0 {
+		return n, nil
+	}
+
+	return 0, fmt.Errorf("read segment file %q at index %d: %w", s.path, index, err)
+}
+
+// close closes the segment read handle.
+func (s *segment) close() (err error) {
+	if err = s.f.Close(); err != nil {
+		return fmt.Errorf("close segment file: %w", err)
+	}
+
+	return nil
+}
+
+// remove deletes the segment file and its creation record.
+func (s *segment) remove() (err error) {
+	if err = os.Remove(s.path); err != nil {
+		return fmt.Errorf("remove segment file: %w", err)
+	}
+
+	if err = os.Remove(s.path + segmentCreatedExt); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove segment creation record: %w", err)
+	}
+
+	return nil
+}
+
+// writeSegmentCreated records the creation time of the segment at path.
+func writeSegmentCreated(path string, created time.Time, perm os.FileMode) (err error) {
+	var bs []byte
+	bs = strconv.AppendInt(bs, created.UnixNano(), 10)
+	if err = os.WriteFile(path+segmentCreatedExt, bs, perm); err != nil {
+		return fmt.Errorf("write segment creation record: %w", err)
+	}
+
+	return nil
+}
+
+// readSegmentCreated returns the recorded creation time of the segment at
+// path. Segments without a readable record, such as those written by older
+// versions, fall back to their modification time.
+func readSegmentCreated(path string, fallback time.Time) (created time.Time) {
+	var (
+		bs    []byte
+		nanos int64
+		err   error
+	)
+
+	if bs, err = os.ReadFile(path + segmentCreatedExt); err != nil {
+		return fallback
+	}
+
+	if nanos, err = strconv.ParseInt(string(bs), 10, 64); err != nil {
+		return fallback
+	}
+
+	return time.Unix(0, nanos)
+}
+
+// segmentName returns the file name for a segment starting at base.
+func segmentName(base int64) (name string) {
+	return fmt.Sprintf("%020d%s", base, segmentExt)
+}
+
+// parseSegmentName returns the base offset encoded in a segment file name.
+func parseSegmentName(name string) (base int64, ok bool) {
+	if !strings.HasSuffix(name, segmentExt) {
+		return 0, false
+	}
+
+	var err error
+	if base, err = strconv.ParseInt(strings.TrimSuffix(name, segmentExt), 10, 64); err != nil {
+		return 0, false
+	}
+
+	return base, true
+}
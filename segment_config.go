@@ -0,0 +1,29 @@
+package streambuf
+
+import "time"
+
+// SegmentConfig controls when a segmented Buffer rolls over to a new segment file.
+// A zero value for a field disables that roll trigger.
+type SegmentConfig struct {
+	// MaxBytes rolls to a new segment once the active segment would exceed this size.
+	// A single write is never split across segments.
+	MaxBytes int64
+	// MaxAge rolls to a new segment once the active segment is older than this duration.
+	// Creation times are kept in a ".created" file next to each segment, so
+	// age is measured from creation across restarts.
+	MaxAge time.Duration
+}
+
+// shouldRoll reports whether a write of n bytes to tail requires a new segment.
+func (c SegmentConfig) shouldRoll(tail *segment, n int64, now time.Time) (ok bool) {
+	switch {
+	case tail.size == 0:
+		return false
+	case c.MaxBytes > 0 && tail.size+n > c.MaxBytes:
+		return true
+	case c.MaxAge > 0 && now.Sub(tail.created) >= c.MaxAge:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,179 @@
+package streambuf
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// newSegments scans dir for existing segment files and opens a read handle for each.
+// An empty directory starts with a single segment at offset 0.
+func newSegments(dir string, perm os.FileMode) (out *segments, err error) {
+	var (
+		s     segments
+		bases []int64
+	)
+
+	s.dir = dir
+	s.perm = perm
+	if bases, err = scanSegments(dir); err != nil {
+		return nil, err
+	}
+
+	if len(bases) == 0 {
+		bases = append(bases, 0)
+	}
+
+	for _, base := range bases {
+		if err = s.open(base); err != nil {
+			_ = s.close()
+			return nil, err
+		}
+	}
+
+	return &s, nil
+}
+
+// segments is the ordered set of segment files shared by the segmented backends.
+type segments struct {
+	mux sync.RWMutex
+
+	dir  string
+	perm os.FileMode
+	list []*segment
+}
+
+// tail returns the active segment that receives writes.
+func (s *segments) tail() (out *segment) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.list[len(s.list)-1]
+}
+
+// end returns the absolute offset immediately after the last byte of the
+// active segment. It reads the size under the lock that grow writes it under.
+func (s *segments) end() (offset int64) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.list[len(s.list)-1].end()
+}
+
+// grow records n bytes appended to the active segment.
+func (s *segments) grow(n int64) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.list[len(s.list)-1].size += n
+}
+
+// roll starts a new segment immediately after the active segment.
+func (s *segments) roll() (out *segment, err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	base := s.list[len(s.list)-1].end()
+	if err = s.open(base); err != nil {
+		return nil, err
+	}
+
+	return s.list[len(s.list)-1], nil
+}
+
+// unroll discards seg, the segment started by a roll that could not be
+// completed, so the previous segment becomes active again.
+func (s *segments) unroll(seg *segment) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.list[len(s.list)-1] != seg {
+		return
+	}
+
+	s.list = s.list[:len(s.list)-1]
+	_ = seg.close()
+	_ = seg.remove()
+}
+
+// readAt copies bytes from the segment containing index into in.
+// Reads never span segments, so callers may receive fewer bytes than requested.
+func (s *segments) readAt(in []byte, index int64) (n int, err error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	i := sort.Search(len(s.list), func(i int) bool {
+		return s.list[i].base > index
+	}) - 1
+
+	switch {
+	case i < 0:
+		return 0, newOffsetEvictedError(index, s.list[0].base)
+	case index < s.list[i].end():
+		return s.list[i].readAt(in, index)
+	case i < len(s.list)-1:
+		// A gap between segments means the missing segment was removed.
+		return 0, newOffsetEvictedError(index, s.list[i+1].base)
+	default:
+		return 0, io.EOF
+	}
+}
+
+// removeBefore closes and deletes every inactive segment that ends at or before offset.
+func (s *segments) removeBefore(offset int64) (err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for len(s.list) > 1 && s.list[0].end() <= offset {
+		if err = s.list[0].close(); err != nil {
+			return err
+		}
+
+		if err = s.list[0].remove(); err != nil {
+			return err
+		}
+
+		s.list = s.list[1:]
+	}
+
+	return nil
+}
+
+// close closes the read handle of every segment.
+func (s *segments) close() (err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var closeErr error
+	for _, seg := range s.list {
+		if closeErr = seg.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// open appends a segment starting at base. Callers must hold the write lock
+// or have exclusive access.
+func (s *segments) open(base int64) (err error) {
+	var seg *segment
+	if seg, err = newSegment(s.dir, base, s.perm); err != nil {
+		return err
+	}
+
+	s.list = append(s.list, seg)
+	return nil
+}
+
+// scanSegments returns the sorted base offsets of the segment files within dir.
+func scanSegments(dir string) (bases []int64, err error) {
+	var entries []os.DirEntry
+	if entries, err = os.ReadDir(dir); err != nil {
+		return nil, fmt.Errorf("read segment directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if base, ok := parseSegmentName(entry.Name()); ok && !entry.IsDir() {
+			bases = append(bases, base)
+		}
+	}
+
+	slices.Sort(bases)
+	return bases, nil
+}
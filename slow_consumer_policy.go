@@ -0,0 +1,26 @@
+package streambuf
+
+import "time"
+
+// SlowConsumerPolicy describes how readers that fall too far behind are handled.
+type SlowConsumerPolicy struct {
+	// MaxLag is the number of unread bytes above which a reader is slow.
+	MaxLag int64
+	// Interval is how often reader lag is checked.
+	Interval time.Duration
+	// OnSlow, when set, is called with a snapshot of every slow reader on
+	// each check. It runs on the checking goroutine and should not block.
+	OnSlow func(info ReaderInfo)
+	// Disconnect makes reads on slow readers return ErrSlowConsumer. The
+	// reader must still be closed.
+	Disconnect bool
+}
+
+// validate returns an error for a policy that cannot be applied.
+func (p SlowConsumerPolicy) validate() (err error) {
+	if p.Interval <= 0 {
+		return ErrInvalidInterval
+	}
+
+	return nil
+}
@@ -4,37 +4,75 @@ import (
 	"context"
 	"io"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // NewStream constructs a read-only file-backed Stream.
-func NewStream(filepath string) (out *Stream, err error) {
-	var r readable
-	if r, err = newReadableFile(filepath); err != nil {
+func NewStream(filepath string, opts ...Option) (out *Stream, err error) {
+	c := newConfig(opts)
+	if err = c.validateFor(mmapOption); err != nil {
+		return nil, err
+	}
+
+	var r ReadableBackend
+	if r, err = newFileReadable(filepath, c); err != nil {
 		return nil, err
 	}
 
 	var s Stream
-	s.stream = newStreamWithReadable(r)
+	s.stream = newStreamWithReadable(r, c)
 	return &s, nil
 }
 
 // NewMemoryStream constructs a read-only memory-backed Stream over bs.
-func NewMemoryStream(bs []byte) (out *Stream) {
+func NewMemoryStream(bs []byte, opts ...Option) (out *Stream) {
 	var s Stream
+	c := newConfig(opts)
+	c.warnInvalid(0)
 	r := newReadableMemory(newMemory(bs))
-	s.stream = newStreamWithReadable(r)
+	s.stream = newStreamWithReadable(r, c)
 	return &s
 }
 
+// NewStreamWithBackend constructs a read-only Stream over custom storage that
+// satisfies the ReadableBackend contract.
+func NewStreamWithBackend(r ReadableBackend, opts ...Option) (out *Stream, err error) {
+	if r == nil {
+		return nil, ErrNilBackend
+	}
+
+	c := newConfig(opts)
+	if err = c.validateFor(0); err != nil {
+		return nil, err
+	}
+
+	var s Stream
+	s.stream = newStreamWithReadable(r, c)
+	return &s, nil
+}
+
 // Stream is a thread-safe read-only stream with reader support.
 type Stream struct {
 	*stream
 }
 
-func newStreamWithReadable(r readable) (out *stream) {
+func newStreamWithReadable(r ReadableBackend, c *config) (out *stream) {
 	var s stream
+	s.c = c
 	s.r = r
 	s.waiter = newWaiter()
+	s.readers = newReaderRegistry()
+	if c.backpressure > 0 {
+		s.advanced = newWaiter()
+	}
+
+	if p := c.slowConsumer; p != nil && p.Interval > 0 {
+		s.monitor = newPeriodic("slow-consumer", p.Interval, c.logger, func(now time.Time) (err error) {
+			return s.checkSlowConsumers(*p)
+		})
+	}
+
 	return &s
 }
 
@@ -43,22 +81,53 @@ type stream struct {
 	mux sync.RWMutex
 	wg  sync.WaitGroup
 
-	r      readable
+	c      *config
+	r      ReadableBackend
 	waiter *waiter
 
+	// readers tracks live readers for Readers and slow consumer checks.
+	readers *readerRegistry
+	monitor *periodic
+	// advanced is refreshed when a reader moves or closes, waking writers
+	// blocked by backpressure. It is nil unless WithBackpressure is set.
+	advanced *waiter
+
+	// low is the low-water mark, the oldest offset readers may access.
+	low atomic.Int64
+	// rewinds holds the latest truncation or rotation of a followed file.
+	rewinds atomic.Pointer[rewind]
+
+	// commits is odd while a transaction is being appended from commitStart,
+	// letting readers skip bytes of a partially appended commit.
+	commits     atomic.Uint64
+	commitStart atomic.Int64
+
 	closed bool
 }
 
 // Reader returns a new io.ReadSeekCloser that tracks its own read offset and
-// supports seeking relative to the start or current position.
+// supports seeking relative to the start, current position, or end.
 // When the reader reaches the current end, Read returns EOF instead of waiting
 // for future bytes. It returns ErrIsClosed if the stream is closed.
 func (s *stream) Reader() (r io.ReadSeekCloser, err error) {
-	if err = s.checkoutReader(); err != nil {
+	var rd *reader
+	if rd, err = s.openReader(false); err != nil {
+		return nil, err
+	}
+
+	return rd, nil
+}
+
+// Readers returns a snapshot of every open reader ordered by ID, including
+// each reader's offset, last read time, and lag behind the current size.
+func (s *stream) Readers() (out []ReaderInfo, err error) {
+	var size int64
+	if size, err = s.Size(); err != nil {
 		return nil, err
 	}
 
-	return newReader(s, false), nil
+	out, _ = s.readers.snapshot(size)
+	return out, nil
 }
 
 // Close closes the stream and signals waiting readers.
@@ -82,6 +151,7 @@ func (s *stream) CloseAndWait(ctx context.Context) (err error) {
 	}
 
 	s.closed = true
+	s.stopReaderTasks()
 
 	if err = s.r.Close(); err != nil {
 		return err
@@ -95,6 +165,196 @@ func (s *stream) CloseAndWait(ctx context.Context) (err error) {
 	return nil
 }
 
+// Size returns the offset immediately after the last readable byte.
+// Bytes of a transaction that is still being committed are excluded.
+// It returns ErrIsClosed if the stream is closed.
+func (s *stream) Size() (n int64, err error) {
+	for {
+		seq := s.commits.Load()
+		if n, err = s.r.Size(); err != nil {
+			return 0, err
+		}
+
+		if seq%2 == 1 {
+			n = min(n, s.commitStart.Load())
+		}
+
+		if s.commits.Load() == seq {
+			return n, nil
+		}
+	}
+}
+
+// LowWaterMark returns the oldest offset readers may access.
+// Reads and seeks below it return an *OffsetEvictedError.
+func (s *stream) LowWaterMark() (offset int64) {
+	return s.low.Load()
+}
+
+func (s *stream) raiseLowWaterMark(offset int64) (raised bool) {
+	for {
+		current := s.low.Load()
+		if offset <= current {
+			return false
+		}
+
+		if s.low.CompareAndSwap(current, offset) {
+			return true
+		}
+	}
+}
+
+func (s *stream) checkRetained(index int64) (err error) {
+	if low := s.low.Load(); index < low {
+		return newOffsetEvictedError(index, low)
+	}
+
+	return nil
+}
+
+// rewind makes every reader restart from offset 0, returning cause once, and
+// wakes waiting readers. Calls must be serialized.
+func (s *stream) rewind(cause error) (err error) {
+	var next rewind
+	next.generation = s.generation() + 1
+	next.cause = cause
+	s.rewinds.Store(&next)
+	return s.waiter.Refresh()
+}
+
+// generation returns the number of rewinds so far.
+func (s *stream) generation() (generation uint64) {
+	if current := s.rewinds.Load(); current != nil {
+		return current.generation
+	}
+
+	return 0
+}
+
+// checkRewind returns the cause of a rewind the reader at generation has not
+// yet observed, along with the current generation.
+func (s *stream) checkRewind(generation uint64) (current uint64, err error) {
+	latest := s.rewinds.Load()
+	if latest == nil || latest.generation == generation {
+		return generation, nil
+	}
+
+	return latest.generation, latest.cause
+}
+
+// beginCommit hides the bytes appended from start until endCommit.
+// Calls must be serialized.
+func (s *stream) beginCommit(start int64) {
+	s.commitStart.Store(start)
+	s.commits.Add(1)
+}
+
+// endCommit makes the bytes hidden by beginCommit visible.
+func (s *stream) endCommit() {
+	s.commits.Add(1)
+}
+
+// readVisible calls read with the number of bytes from index that readers may
+// observe, up to n. A read that overlaps the start or end of a commit is
+// retried, so partially appended commits are never returned. When no bytes at
+// index are visible, read is not called and io.EOF is returned.
+func (s *stream) readVisible(index int64, n int, read func(n int) (read int, err error)) (out int, err error) {
+	for {
+		seq := s.commits.Load()
+		limit := n
+		if seq%2 == 1 {
+			start := s.commitStart.Load()
+			if index >= start {
+				return 0, io.EOF
+			}
+
+			limit = int(min(int64(n), start-index))
+		}
+
+		out, err = read(limit)
+		if s.commits.Load() == seq {
+			return out, err
+		}
+	}
+}
+
+// openReader checks out, constructs, and registers a reader.
+func (s *stream) openReader(tail bool) (r *reader, err error) {
+	if err = s.checkoutReader(); err != nil {
+		return nil, err
+	}
+
+	r = newReader(s, tail)
+	s.readers.add(r)
+	return r, nil
+}
+
+// openDetachedReader constructs and registers a reader for internal use that
+// CloseAndWait does not wait for. Reads fail once the backend is closed.
+func (s *stream) openDetachedReader(tail bool) (r *reader, err error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	if s.closed {
+		return nil, ErrIsClosed
+	}
+
+	r = newReader(s, tail)
+	r.detached = true
+	s.readers.add(r)
+	return r, nil
+}
+
+// checkSlowConsumers applies p to every reader lagging more than p.MaxLag bytes.
+func (s *stream) checkSlowConsumers(p SlowConsumerPolicy) (err error) {
+	if s.isClosed() {
+		return ErrIsClosed
+	}
+
+	var size int64
+	if size, err = s.Size(); err != nil {
+		return err
+	}
+
+	infos, readers := s.readers.snapshot(size)
+	for i, info := range infos {
+		// Disconnected readers are only waiting to be closed.
+		if info.Lag <= p.MaxLag || isClosedChan(readers[i].disconnect.Wait()) {
+			continue
+		}
+
+		s.c.logger.Warn("streambuf: slow consumer", "reader", info.ID, "name", info.Name, "lag", info.Lag)
+		if p.OnSlow != nil {
+			p.OnSlow(info)
+		}
+
+		if p.Disconnect {
+			_ = readers[i].disconnect.Close()
+			s.notifyAdvanced()
+		}
+	}
+
+	return nil
+}
+
+// notifyAdvanced wakes writers blocked by backpressure.
+func (s *stream) notifyAdvanced() {
+	if s.advanced != nil {
+		_ = s.advanced.Refresh()
+	}
+}
+
+// stopReaderTasks stops slow consumer checks and releases writers blocked by
+// backpressure.
+func (s *stream) stopReaderTasks() {
+	if s.monitor != nil {
+		_ = s.monitor.Close()
+	}
+
+	if s.advanced != nil {
+		_ = s.advanced.Close()
+	}
+}
+
 func (s *stream) checkoutReader() (err error) {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
@@ -103,9 +363,16 @@ func (s *stream) checkoutReader() (err error) {
 	}
 
 	s.wg.Add(1)
+	s.c.metrics.ReaderOpened()
 	return nil
 }
 
+// checkinReader releases a reader checked out by checkoutReader.
+func (s *stream) checkinReader() {
+	s.wg.Done()
+	s.c.metrics.ReaderClosed()
+}
+
 func (s *stream) waitUntilDone(ctx context.Context) {
 	select {
 	case <-ctx.Done():
@@ -341,6 +341,37 @@ func Test_Stream_Reader_seek(t *testing.T) {
 			wantPos: 0,
 			wantErr: ErrNegativeIndex,
 		},
+		{
+			name: "memory seek end",
+			init: func(t *testing.T) (s *Stream, err error) {
+				t.Helper()
+				return NewMemoryStream([]byte("hello world")), nil
+			},
+			offset:  -5,
+			whence:  io.SeekEnd,
+			wantPos: 6,
+		},
+		{
+			name: "file seek end",
+			init: func(t *testing.T) (s *Stream, err error) {
+				t.Helper()
+				return newTestFileStream(t, "stream-seek-end-*", []byte("hello world"))
+			},
+			offset:  0,
+			whence:  io.SeekEnd,
+			wantPos: 11,
+		},
+		{
+			name: "file seek end negative clamped to zero",
+			init: func(t *testing.T) (s *Stream, err error) {
+				t.Helper()
+				return newTestFileStream(t, "stream-seek-end-*", []byte("hello world"))
+			},
+			offset:  -12,
+			whence:  io.SeekEnd,
+			wantPos: 0,
+			wantErr: ErrNegativeIndex,
+		},
 	}
 
 	for _, tt := range tests {
@@ -11,8 +11,9 @@ import (
 )
 
 var (
-	// ErrSeekEndNotSupported is returned when seeking relative to the end.
-	// Reader-backed seeks currently support only SeekStart and SeekCurrent.
+	// ErrSeekEndNotSupported was returned when seeking relative to the end.
+	//
+	// Deprecated: every reader supports SeekEnd, so this error is no longer returned.
 	ErrSeekEndNotSupported = errors.New("seek end is not currently supported")
 	// ErrInvalidWhence is returned when Seek receives an unsupported whence value.
 	ErrInvalidWhence = errors.New("invalid seek whence")
@@ -23,6 +24,42 @@ var (
 	ErrCannotWriteToReadOnly = errors.New("cannot write to read-only backend")
 	// ErrIsClosed is returned when an action is attempted on a closed instance.
 	ErrIsClosed = errors.New("cannot perform action on closed instance")
+	// ErrOffsetEvicted is matched by *OffsetEvictedError when a read targets
+	// bytes a bounded backend has already discarded.
+	ErrOffsetEvicted = errors.New("offset has been evicted")
+	// ErrTornTail is returned by Open when the file ends with bytes that were not
+	// fully journaled and the recovery mode does not allow truncating them.
+	ErrTornTail = errors.New("file has a torn tail")
+	// ErrCorruptRecord is returned when a record checksum does not match its
+	// payload or a record header declares more than MaxRecordSize bytes.
+	ErrCorruptRecord = errors.New("corrupt record")
+	// ErrRecordTooLarge is returned when a record exceeds MaxRecordSize.
+	ErrRecordTooLarge = errors.New("record exceeds maximum size")
+	// ErrInvalidSyncPolicy is returned when a SyncPolicy has an unknown mode or
+	// is missing the byte threshold its mode requires.
+	ErrInvalidSyncPolicy = errors.New("invalid sync policy")
+	// ErrTruncated is returned once by readers of a follow Stream after the
+	// followed file shrinks. The reader restarts from offset 0.
+	ErrTruncated = errors.New("followed file was truncated")
+	// ErrRotated is returned once by readers of a follow Stream after the
+	// followed path is replaced by a new file. The reader restarts from offset 0.
+	ErrRotated = errors.New("followed file was rotated")
+	// ErrNilBackend is returned when a backend constructor receives a nil backend.
+	ErrNilBackend = errors.New("backend cannot be nil")
+	// ErrInvalidInterval is returned when a periodic task or timeout receives a
+	// non-positive interval.
+	ErrInvalidInterval = errors.New("invalid interval, must be greater than 0")
+	// ErrSlowConsumer is returned by reads on a reader that a SlowConsumerPolicy
+	// disconnected for falling too far behind. The reader must still be closed.
+	ErrSlowConsumer = errors.New("reader disconnected as a slow consumer")
+	// ErrUnsupportedOption is returned when a constructor receives an Option it
+	// cannot honor.
+	ErrUnsupportedOption = errors.New("option is not supported by this constructor")
+	// ErrStaleDelivery is returned when a Message is acknowledged or nacked
+	// after its visibility timeout expired and the record was redelivered.
+	ErrStaleDelivery = errors.New("message was redelivered after its visibility timeout")
+	// ErrTxDone is returned when a transaction is used after Commit or Rollback.
+	ErrTxDone = errors.New("transaction has already been committed or rolled back")
 )
 
 var expiredContext context.Context
@@ -4,6 +4,8 @@ import (
 	"context"
 	"io"
 	"log"
+	"log/slog"
+	"time"
 )
 
 var exampleBuffer *Buffer
@@ -16,6 +18,20 @@ func ExampleNew() {
 	}
 }
 
+func ExampleNew_options() {
+	var err error
+	exampleBuffer, err = New("path/to/dir",
+		WithSegments(SegmentConfig{MaxBytes: 64 << 20}),
+		WithSync(SyncPolicy{Mode: SyncEveryInterval, Interval: time.Second}),
+		WithRetention(MaxAge(24*time.Hour), time.Minute),
+		WithPermissions(0600),
+		WithLogger(slog.Default()),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
 func ExampleNewStream() {
 	var err error
 	// NewStream constructs a read-only file-backed stream.
@@ -28,6 +44,11 @@ func ExampleNewMemory() {
 	exampleBuffer = NewMemory()
 }
 
+func ExampleNewMemoryRing() {
+	// NewMemoryRing retains at most 1 MiB, discarding the oldest bytes first.
+	exampleBuffer = NewMemoryRing(1 << 20)
+}
+
 func ExampleNewMemoryStream() {
 	bs := []byte("hello world")
 	exampleStream = NewMemoryStream(bs)
@@ -39,6 +60,19 @@ func ExampleBuffer_Write() {
 	}
 }
 
+func ExampleBuffer_Begin() {
+	tx, err := exampleBuffer.Begin()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	_, _ = tx.Write([]byte("header:"))
+	_, _ = tx.Write([]byte("body\n"))
+	if err = tx.Commit(); err != nil {
+		log.Fatal(err)
+	}
+}
+
 func ExampleBuffer_Reader() {
 	var err error
 	if _, err = exampleBuffer.Write([]byte("hello world")); err != nil {
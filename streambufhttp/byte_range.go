@@ -0,0 +1,72 @@
+package streambufhttp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parseByteRange parses a Range header holding a single byte range.
+// ok is false for missing, malformed, or multi-range headers, which are ignored.
+func parseByteRange(header string) (out byteRange, ok bool) {
+	var (
+		spec       string
+		start, end string
+		found      bool
+		err        error
+	)
+
+	if spec, found = strings.CutPrefix(header, "bytes="); !found || strings.Contains(spec, ",") {
+		return out, false
+	}
+
+	if start, end, found = strings.Cut(strings.TrimSpace(spec), "-"); !found {
+		return out, false
+	}
+
+	out.end = -1
+	switch {
+	case start == "":
+		// A suffix range selects the last end bytes.
+		out.suffix = true
+		if out.start, err = strconv.ParseInt(end, 10, 64); err != nil || out.start <= 0 {
+			return out, false
+		}
+
+		return out, true
+	case end == "":
+	default:
+		if out.end, err = strconv.ParseInt(end, 10, 64); err != nil {
+			return out, false
+		}
+	}
+
+	if out.start, err = strconv.ParseInt(start, 10, 64); err != nil || out.start < 0 {
+		return out, false
+	}
+
+	if out.end >= 0 && out.end < out.start {
+		return out, false
+	}
+
+	return out, true
+}
+
+// byteRange is a single range from a Range header.
+type byteRange struct {
+	// start is the first offset, or the suffix length when suffix is set.
+	start int64
+	// end is the last offset, inclusive, or -1 when the range is open.
+	end int64
+
+	suffix bool
+}
+
+// bounded reports whether the range ends at a fixed offset.
+func (b byteRange) bounded() (ok bool) {
+	return !b.suffix && b.end >= 0
+}
+
+// length returns the number of bytes in a bounded range.
+func (b byteRange) length() (n int64) {
+	return b.end - b.start + 1
+}
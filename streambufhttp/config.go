@@ -0,0 +1,18 @@
+package streambufhttp
+
+// newConfig constructs a config with defaults and applies opts in order.
+func newConfig(opts []Option) (out *config) {
+	var c config
+	c.contentType = "application/octet-stream"
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c
+}
+
+// config holds the settings collected from Options.
+type config struct {
+	contentType string
+	etag        string
+}
@@ -0,0 +1,33 @@
+package streambufhttp
+
+import (
+	"context"
+	"io"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// newContextReader binds reads to ctx when r supports it, so a blocked
+// follow read ends with the request.
+func newContextReader(ctx context.Context, r io.Reader) (out io.Reader) {
+	var c contextReader
+	cr, ok := r.(streambuf.ContextReader)
+	if !ok {
+		return r
+	}
+
+	c.ctx = ctx
+	c.r = cr
+	return &c
+}
+
+// contextReader adapts a streambuf.ContextReader to io.Reader for a single request.
+type contextReader struct {
+	ctx context.Context
+	r   streambuf.ContextReader
+}
+
+// Read reads from the underlying reader until the request context ends.
+func (c *contextReader) Read(in []byte) (n int, err error) {
+	return c.r.ReadContext(c.ctx, in)
+}
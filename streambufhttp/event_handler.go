@@ -0,0 +1,156 @@
+package streambufhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// NewEventHandler constructs an EventHandler that emits each line of src as
+// an event. Lines are split on '\n' with an optional preceding '\r'.
+func NewEventHandler(src Source) (out *EventHandler) {
+	var h EventHandler
+	h.open = func() (events eventReader, err error) {
+		var r io.ReadSeekCloser
+		if r, err = src.StreamingReader(); err != nil {
+			return nil, err
+		}
+
+		return newLineEvents(r), nil
+	}
+
+	return &h
+}
+
+// NewRecordEventHandler constructs an EventHandler that emits each record of
+// rb as an event. Records should hold UTF-8 text; line breaks within a record
+// are sent as multiple data lines and rejoined by the client.
+func NewRecordEventHandler(rb *streambuf.RecordBuffer) (out *EventHandler) {
+	var h EventHandler
+	h.open = func() (events eventReader, err error) {
+		var rr *streambuf.RecordReader
+		if rr, err = rb.StreamingReader(); err != nil {
+			return nil, err
+		}
+
+		return newRecordEvents(rb, rr), nil
+	}
+
+	return &h
+}
+
+// EventHandler serves a stream as Server-Sent Events (text/event-stream).
+//
+// Each event's id is the stream offset immediately after it, so a reconnecting
+// client's Last-Event-ID header resumes with the next event. Requests without
+// Last-Event-ID start at the low-water mark, the oldest retained offset, and a
+// Last-Event-ID that has been evicted resumes there as well. For record
+// streams, a Last-Event-ID that is not a record boundary is rejected with 400
+// Bad Request.
+//
+// Line streams skip the line the low-water mark falls within, as it may have
+// been partly discarded, and also resume at the mark when retention overtakes
+// a slow client. Record streams resume only when the mark is a record
+// boundary; otherwise the request fails with 416 Requested Range Not
+// Satisfiable, and an eviction during the response ends it.
+// The response ends when the stream closes or the request context ends.
+type EventHandler struct {
+	open func() (events eventReader, err error)
+}
+
+// ServeHTTP streams events until the stream closes or the request ends.
+func (h *EventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		events eventReader
+		err    error
+	)
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if events, err = h.open(); err != nil {
+		writeError(w, err)
+		return
+	}
+	defer events.Close()
+
+	// Closing the reader unblocks a pending Next once the client goes away.
+	stop := context.AfterFunc(r.Context(), func() { _ = events.Close() })
+	defer stop()
+
+	if err = resume(events, r.Header.Get("Last-Event-ID")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	_ = http.NewResponseController(w).Flush()
+	h.stream(newFlushWriter(w), events)
+}
+
+// stream writes events to w until the reader or the response fails.
+func (h *EventHandler) stream(w *flushWriter, events eventReader) {
+	var (
+		data []byte
+		id   int64
+		buf  bytes.Buffer
+		err  error
+	)
+
+	for {
+		switch data, id, err = events.Next(); {
+		case errors.Is(err, streambuf.ErrCorruptRecord):
+			continue
+		case err != nil:
+			return
+		}
+
+		buf.Reset()
+		writeEvent(&buf, id, data)
+		if _, err = w.Write(buf.Bytes()); err != nil {
+			return
+		}
+	}
+}
+
+// resume seeks events to the offset in a Last-Event-ID header, or to the start
+// of the retained stream without one.
+func resume(events eventReader, lastEventID string) (err error) {
+	var offset int64
+	if lastEventID == "" {
+		return events.SeekOffset(0)
+	}
+
+	if offset, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || offset < 0 {
+		return errInvalidLastEventID
+	}
+
+	return events.SeekOffset(offset)
+}
+
+// writeEvent encodes one event, splitting data on line breaks as SSE requires.
+func writeEvent(buf *bytes.Buffer, id int64, data []byte) {
+	buf.WriteString("id: ")
+	buf.WriteString(strconv.FormatInt(id, 10))
+	buf.WriteByte('\n')
+
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	data = bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	buf.WriteByte('\n')
+}
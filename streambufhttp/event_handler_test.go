@@ -0,0 +1,191 @@
+package streambufhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+func Test_EventHandler_ServeHTTP(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		init func(t *testing.T, b *streambuf.Buffer) (h *EventHandler)
+
+		lastEventID string
+
+		wantStatus int
+		wantBody   string
+	}
+
+	lines := func(t *testing.T, b *streambuf.Buffer) (h *EventHandler) {
+		if _, err := b.Write([]byte("a\nb\r\nc\npartial")); err != nil {
+			t.Fatal(err)
+		}
+
+		return NewEventHandler(b)
+	}
+
+	records := func(t *testing.T, b *streambuf.Buffer) (h *EventHandler) {
+		rb := streambuf.NewRecordBuffer(b, true)
+		for _, rec := range []string{"first", "two\nlines"} {
+			if err := rb.WriteRecord([]byte(rec)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return NewRecordEventHandler(rb)
+	}
+
+	truncated := func(init func(t *testing.T, b *streambuf.Buffer) (h *EventHandler), offset int64) func(t *testing.T, b *streambuf.Buffer) (h *EventHandler) {
+		return func(t *testing.T, b *streambuf.Buffer) (h *EventHandler) {
+			h = init(t, b)
+			truncate(offset)(t, b)
+			return h
+		}
+	}
+
+	tests := []testcase{
+		{
+			name:       "lines",
+			init:       lines,
+			wantStatus: http.StatusOK,
+			wantBody:   "id: 2\ndata: a\n\nid: 5\ndata: b\n\nid: 7\ndata: c\n\n",
+		},
+		{
+			name:        "lines resumed from Last-Event-ID",
+			init:        lines,
+			lastEventID: "2",
+			wantStatus:  http.StatusOK,
+			wantBody:    "id: 5\ndata: b\n\nid: 7\ndata: c\n\n",
+		},
+		{
+			name:       "records",
+			init:       records,
+			wantStatus: http.StatusOK,
+			wantBody:   "id: 10\ndata: first\n\nid: 24\ndata: two\ndata: lines\n\n",
+		},
+		{
+			name:        "records resumed from Last-Event-ID",
+			init:        records,
+			lastEventID: "10",
+			wantStatus:  http.StatusOK,
+			wantBody:    "id: 24\ndata: two\ndata: lines\n\n",
+		},
+		{
+			name:        "records misaligned Last-Event-ID",
+			init:        records,
+			lastEventID: "3",
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    "invalid Last-Event-ID\n",
+		},
+		{
+			name:        "records Last-Event-ID past the end",
+			init:        records,
+			lastEventID: "25",
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    "invalid Last-Event-ID\n",
+		},
+		{
+			name:       "lines truncated within a line",
+			init:       truncated(lines, 3),
+			wantStatus: http.StatusOK,
+			wantBody:   "id: 7\ndata: c\n\n",
+		},
+		{
+			name:        "lines evicted Last-Event-ID",
+			init:        truncated(lines, 3),
+			lastEventID: "2",
+			wantStatus:  http.StatusOK,
+			wantBody:    "id: 7\ndata: c\n\n",
+		},
+		{
+			name:       "records truncated at a boundary",
+			init:       truncated(records, 10),
+			wantStatus: http.StatusOK,
+			wantBody:   "id: 24\ndata: two\ndata: lines\n\n",
+		},
+		{
+			name:        "records evicted Last-Event-ID",
+			init:        truncated(records, 10),
+			lastEventID: "5",
+			wantStatus:  http.StatusOK,
+			wantBody:    "id: 24\ndata: two\ndata: lines\n\n",
+		},
+		{
+			name:       "records truncated within a record",
+			init:       truncated(records, 12),
+			wantStatus: http.StatusRequestedRangeNotSatisfiable,
+			wantBody:   "offset 0 has been evicted, oldest available offset is 12\n",
+		},
+		{
+			name:        "invalid Last-Event-ID",
+			init:        lines,
+			lastEventID: "abc",
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    "invalid Last-Event-ID\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := streambuf.NewMemory()
+			t.Cleanup(func() { _ = b.Close() })
+
+			h := tt.init(t, b)
+			closeLater(t, b)
+
+			req := httptest.NewRequest(http.MethodGet, "/events", nil)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() invalid status, expected <%d> and received <%d>", tt.wantStatus, rec.Code)
+			}
+
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Fatalf("ServeHTTP() invalid body, expected <%q> and received <%q>", tt.wantBody, got)
+			}
+		})
+	}
+}
+
+func Test_EventHandler_ServeHTTP_client_disconnect(t *testing.T) {
+	var (
+		m    testMetrics
+		resp *http.Response
+		err  error
+	)
+
+	b := streambuf.NewMemory(streambuf.WithMetrics(&m))
+	t.Cleanup(func() { _ = b.Close() })
+
+	srv := httptest.NewServer(NewEventHandler(b))
+	t.Cleanup(srv.Close)
+
+	if resp, err = http.Get(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("ServeHTTP() invalid Content-Type, expected <text/event-stream> and received <%q>", got)
+	}
+
+	// Closing the body disconnects the client, which must close the server's reader.
+	_ = resp.Body.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for m.opened.Load() == 0 || m.closed.Load() != m.opened.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("ServeHTTP() did not close the reader when the client disconnected")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
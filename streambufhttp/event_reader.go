@@ -0,0 +1,12 @@
+package streambufhttp
+
+// eventReader yields the events of one SSE response.
+type eventReader interface {
+	// SeekOffset resumes after the event whose id is offset, or at the
+	// low-water mark when offset has been evicted.
+	SeekOffset(offset int64) (err error)
+	// Next returns the next event payload and its id, the stream offset
+	// immediately after the event.
+	Next() (data []byte, id int64, err error)
+	Close() (err error)
+}
@@ -0,0 +1,33 @@
+package streambufhttp
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// newFlushWriter constructs a writer that flushes w after every write.
+func newFlushWriter(w http.ResponseWriter) (out *flushWriter) {
+	var f flushWriter
+	f.w = w
+	f.rc = http.NewResponseController(w)
+	return &f
+}
+
+// flushWriter pushes each chunk to the client as soon as it is written.
+type flushWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// Write writes bs to the response and flushes it.
+func (f *flushWriter) Write(bs []byte) (n int, err error) {
+	if n, err = f.w.Write(bs); err != nil {
+		return n, fmt.Errorf("write response: %w", err)
+	}
+
+	if err = f.rc.Flush(); err != nil {
+		return n, fmt.Errorf("flush response: %w", err)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,244 @@
+// Package streambufhttp serves streambuf streams over HTTP.
+package streambufhttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// OffsetHeader reports the stream offset of the first byte of a follow
+// response, or of the retained window a snapshot covers.
+const OffsetHeader = "Streambuf-Offset"
+
+// NewHandler constructs a Handler that serves src.
+func NewHandler(src Source, opts ...Option) (out *Handler) {
+	var h Handler
+	h.src = src
+	h.c = newConfig(opts)
+	return &h
+}
+
+// Handler serves a stream over HTTP with a new reader per request.
+//
+// By default a request receives a snapshot of the current stream through
+// http.ServeContent, which honors Range, If-Range, and conditional headers.
+// A snapshot covers the bytes from the low-water mark onward, so once the head
+// of the stream has been discarded, Content-Length and ranges are relative to
+// the retained window and OffsetHeader reports where it starts.
+// A request with the follow query parameter set to true receives a
+// StreamingReader instead: the response is streamed with chunked encoding and
+// flushed after every write until the stream closes or the request context ends.
+// Follow requests honor a single Range; open-ended ranges respond with 200 and
+// OffsetHeader, and bounded ranges respond with 206 once the range is written.
+type Handler struct {
+	src Source
+	c   *config
+}
+
+// ServeHTTP serves the stream in snapshot or follow mode.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		follow bool
+		err    error
+	)
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if value := r.URL.Query().Get("follow"); value != "" {
+		if follow, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "invalid follow parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", h.c.contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if follow {
+		h.serveFollow(w, r)
+		return
+	}
+
+	h.serveSnapshot(w, r)
+}
+
+// serveSnapshot serves the retained bytes written so far. The ETag is
+// qualified with the window, so If-None-Match only matches while the stream
+// has neither grown nor been truncated, and If-Range only matches a snapshot
+// of the same window.
+func (h *Handler) serveSnapshot(w http.ResponseWriter, r *http.Request) {
+	var (
+		reader io.ReadSeekCloser
+		low    int64
+		size   int64
+		err    error
+	)
+
+	if reader, err = h.src.Reader(); err != nil {
+		writeError(w, err)
+		return
+	}
+	defer reader.Close()
+
+	// Seeking to 0 clamps to the low-water mark once the head is discarded.
+	if low, err = reader.Seek(0, io.SeekStart); err != nil && !errors.Is(err, streambuf.ErrOffsetEvicted) {
+		writeError(w, err)
+		return
+	}
+
+	if size, err = reader.Seek(0, io.SeekEnd); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set(OffsetHeader, strconv.FormatInt(low, 10))
+	if h.c.etag != "" {
+		w.Header().Set("ETag", windowETag(h.c.etag, low, size))
+	}
+
+	if r.Header.Get("If-Range") != "" {
+		ifLow, ok := h.ifRangeWindow(r)
+		r = r.Clone(r.Context())
+		r.Header.Del("If-Range")
+		if !ok || ifLow != low {
+			r.Header.Del("Range")
+		}
+	}
+
+	http.ServeContent(w, r, "", time.Time{}, newWindowReader(reader, low))
+}
+
+// serveFollow streams bytes as they are written until the stream closes, the
+// range ends, or the request context ends.
+func (h *Handler) serveFollow(w http.ResponseWriter, r *http.Request) {
+	var (
+		reader io.ReadSeekCloser
+		rng    byteRange
+		ok     bool
+		start  int64
+		err    error
+	)
+
+	if reader, err = h.src.StreamingReader(); err != nil {
+		writeError(w, err)
+		return
+	}
+	defer reader.Close()
+
+	// Follow ranges are stream offsets, so an If-Range of any window matches.
+	if rng, ok = parseByteRange(r.Header.Get("Range")); ok && r.Header.Get("If-Range") != "" {
+		_, ok = h.ifRangeWindow(r)
+	}
+
+	if start, err = seekRange(reader, rng, ok); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set(OffsetHeader, strconv.FormatInt(start, 10))
+	if h.c.etag != "" {
+		w.Header().Set("ETag", h.c.etag)
+	}
+
+	var src io.Reader = newContextReader(r.Context(), reader)
+	switch {
+	case ok && rng.bounded():
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/*", rng.start, rng.end))
+		w.Header().Set("Content-Length", strconv.FormatInt(rng.length(), 10))
+		w.WriteHeader(http.StatusPartialContent)
+		src = io.LimitReader(src, rng.length())
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = io.CopyBuffer(newFlushWriter(w), src, make([]byte, 32*1024))
+}
+
+// ifRangeWindow reports whether the If-Range header of r is a strong match
+// with the configured ETag or a snapshot ETag derived from it, along with the
+// low-water mark of the window it names.
+func (h *Handler) ifRangeWindow(r *http.Request) (low int64, ok bool) {
+	ifRange := r.Header.Get("If-Range")
+	switch {
+	case h.c.etag == "" || strings.HasPrefix(h.c.etag, "W/"):
+		return 0, false
+	case ifRange == h.c.etag:
+		return 0, true
+	}
+
+	// Bytes at an offset never change, so a snapshot ETag of any size matches.
+	prefix := strings.TrimSuffix(h.c.etag, `"`) + "-"
+	if !strings.HasPrefix(ifRange, prefix) || !strings.HasSuffix(ifRange, `"`) {
+		return 0, false
+	}
+
+	window := ifRange[len(prefix) : len(ifRange)-1]
+	if lowPart, sizePart, found := strings.Cut(window, "-"); found {
+		if low, err := strconv.ParseInt(lowPart, 10, 64); err == nil && low > 0 {
+			_, err = strconv.ParseUint(sizePart, 10, 64)
+			return low, err == nil
+		}
+
+		return 0, false
+	}
+
+	_, err := strconv.ParseUint(window, 10, 64)
+	return 0, err == nil
+}
+
+// windowETag qualifies etag with a snapshot window, such as "v1" to "v1-11",
+// or "v1-6-11" once the bytes before offset 6 have been discarded.
+func windowETag(etag string, low, size int64) (out string) {
+	out = strings.TrimSuffix(etag, `"`) + "-"
+	if low > 0 {
+		out += strconv.FormatInt(low, 10) + "-"
+	}
+
+	return out + strconv.FormatInt(size, 10) + `"`
+}
+
+// seekRange positions reader at the start of rng and returns the resulting offset.
+func seekRange(reader io.Seeker, rng byteRange, ok bool) (start int64, err error) {
+	switch {
+	case !ok:
+		return reader.Seek(0, io.SeekCurrent)
+	case rng.suffix:
+		start, err = reader.Seek(-rng.start, io.SeekEnd)
+		if errors.Is(err, streambuf.ErrNegativeIndex) {
+			return start, nil
+		}
+
+		return start, err
+	default:
+		return reader.Seek(rng.start, io.SeekStart)
+	}
+}
+
+// writeError maps stream errors to HTTP responses.
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, streambuf.ErrIsClosed):
+		http.Error(w, "stream is closed", http.StatusServiceUnavailable)
+	case errors.Is(err, errInvalidLastEventID):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, streambuf.ErrOffsetEvicted):
+		http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+	default:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,335 @@
+package streambufhttp
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+type testMetrics struct {
+	opened atomic.Int64
+	closed atomic.Int64
+}
+
+func (m *testMetrics) Written(n int) {}
+func (m *testMetrics) ReaderOpened() { m.opened.Add(1) }
+func (m *testMetrics) ReaderClosed() { m.closed.Add(1) }
+
+func Test_Handler_ServeHTTP(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		init   func(t *testing.T, b *streambuf.Buffer)
+		method string
+		target string
+		header map[string]string
+
+		wantStatus int
+		wantBody   string
+		wantHeader map[string]string
+	}
+
+	tests := []testcase{
+		{
+			name:       "snapshot",
+			target:     "/",
+			wantStatus: http.StatusOK,
+			wantBody:   "hello world",
+			wantHeader: map[string]string{"Content-Type": "text/plain", "ETag": `"v1-11"`},
+		},
+		{
+			name:       "snapshot range",
+			target:     "/",
+			header:     map[string]string{"Range": "bytes=6-"},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "world",
+			wantHeader: map[string]string{"Content-Range": "bytes 6-10/11"},
+		},
+		{
+			name:       "snapshot if-range match",
+			target:     "/",
+			header:     map[string]string{"Range": "bytes=0-4", "If-Range": `"v1"`},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "hello",
+		},
+		{
+			name:       "snapshot if-range match of an earlier snapshot",
+			target:     "/",
+			header:     map[string]string{"Range": "bytes=0-4", "If-Range": `"v1-5"`},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "hello",
+		},
+		{
+			name:       "snapshot if-none-match",
+			target:     "/",
+			header:     map[string]string{"If-None-Match": `"v1-11"`},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "snapshot if-none-match after growth",
+			target:     "/",
+			header:     map[string]string{"If-None-Match": `"v1-5"`},
+			wantStatus: http.StatusOK,
+			wantBody:   "hello world",
+		},
+		{
+			name:       "snapshot if-range mismatch",
+			target:     "/",
+			header:     map[string]string{"Range": "bytes=0-4", "If-Range": `"v0"`},
+			wantStatus: http.StatusOK,
+			wantBody:   "hello world",
+		},
+		{
+			name:       "snapshot truncated",
+			target:     "/",
+			init:       truncate(6),
+			wantStatus: http.StatusOK,
+			wantBody:   "world",
+			wantHeader: map[string]string{"Content-Length": "5", "ETag": `"v1-6-11"`, "Streambuf-Offset": "6"},
+		},
+		{
+			name:       "snapshot truncated range",
+			target:     "/",
+			header:     map[string]string{"Range": "bytes=1-3"},
+			init:       truncate(6),
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "orl",
+			wantHeader: map[string]string{"Content-Range": "bytes 1-3/5"},
+		},
+		{
+			name:       "snapshot truncated if-range match",
+			target:     "/",
+			header:     map[string]string{"Range": "bytes=1-3", "If-Range": `"v1-6-9"`},
+			init:       truncate(6),
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "orl",
+		},
+		{
+			name:       "snapshot truncated if-range of another window",
+			target:     "/",
+			header:     map[string]string{"Range": "bytes=1-3", "If-Range": `"v1-11"`},
+			init:       truncate(6),
+			wantStatus: http.StatusOK,
+			wantBody:   "world",
+		},
+		{
+			name:       "follow bounded range",
+			target:     "/?follow=true",
+			header:     map[string]string{"Range": "bytes=6-12"},
+			init:       writeLater(" and more"),
+			wantStatus: http.StatusPartialContent,
+			wantBody:   "world a",
+			wantHeader: map[string]string{"Content-Range": "bytes 6-12/*", "Streambuf-Offset": "6"},
+		},
+		{
+			name:       "follow suffix range",
+			target:     "/?follow=1",
+			header:     map[string]string{"Range": "bytes=-5"},
+			init:       closeLater,
+			wantStatus: http.StatusOK,
+			wantBody:   "world",
+			wantHeader: map[string]string{"Streambuf-Offset": "6"},
+		},
+		{
+			name:       "follow if-range mismatch",
+			target:     "/?follow=1",
+			header:     map[string]string{"Range": "bytes=6-", "If-Range": `"v0"`},
+			init:       closeLater,
+			wantStatus: http.StatusOK,
+			wantBody:   "hello world",
+			wantHeader: map[string]string{"Streambuf-Offset": "0"},
+		},
+		{
+			name:       "invalid follow parameter",
+			target:     "/?follow=maybe",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "invalid follow parameter\n",
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			target:     "/",
+			wantStatus: http.StatusMethodNotAllowed,
+			wantBody:   "method not allowed\n",
+			wantHeader: map[string]string{"Allow": "GET, HEAD"},
+		},
+		{
+			name:   "closed stream",
+			target: "/",
+			init: func(t *testing.T, b *streambuf.Buffer) {
+				_ = b.Close()
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   "stream is closed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			b := streambuf.NewMemory()
+			t.Cleanup(func() { _ = b.Close() })
+
+			if _, err = b.Write([]byte("hello world")); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.init != nil {
+				tt.init(t, b)
+			}
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, tt.target, nil)
+			for key, value := range tt.header {
+				req.Header.Set(key, value)
+			}
+
+			rec := httptest.NewRecorder()
+			h := NewHandler(b, WithContentType("text/plain"), WithETag(`"v1"`))
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() invalid status, expected <%d> and received <%d>", tt.wantStatus, rec.Code)
+			}
+
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Fatalf("ServeHTTP() invalid body, expected <%q> and received <%q>", tt.wantBody, got)
+			}
+
+			for key, want := range tt.wantHeader {
+				if got := rec.Header().Get(key); got != want {
+					t.Fatalf("ServeHTTP() invalid %s header, expected <%q> and received <%q>", key, want, got)
+				}
+			}
+		})
+	}
+}
+
+func Test_Handler_ServeHTTP_if_none_match_growth(t *testing.T) {
+	var err error
+	b := streambuf.NewMemory()
+	t.Cleanup(func() { _ = b.Close() })
+
+	if _, err = b.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHandler(b, WithETag(`"v1"`))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	etag := rec.Header().Get("ETag")
+
+	if _, err = b.Write([]byte(" world")); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ServeHTTP() invalid status, expected <%d> and received <%d>", http.StatusOK, rec.Code)
+	}
+
+	if got := rec.Body.String(); got != "hello world" {
+		t.Fatalf("ServeHTTP() invalid body, expected <%q> and received <%q>", "hello world", got)
+	}
+}
+
+func Test_Handler_ServeHTTP_follow(t *testing.T) {
+	var (
+		m    testMetrics
+		req  *http.Request
+		resp *http.Response
+		line string
+		err  error
+	)
+
+	b := streambuf.NewMemory(streambuf.WithMetrics(&m))
+	t.Cleanup(func() { _ = b.Close() })
+
+	srv := httptest.NewServer(NewHandler(b))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err = b.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?follow=true", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if len(resp.TransferEncoding) == 0 || resp.TransferEncoding[0] != "chunked" {
+		t.Fatalf("ServeHTTP() invalid transfer encoding, expected <chunked> and received <%v>", resp.TransferEncoding)
+	}
+
+	br := bufio.NewReader(resp.Body)
+	for _, want := range []string{"first\n", "second\n"} {
+		if line, err = br.ReadString('\n'); err != nil {
+			t.Fatalf("Read() unexpected error: %v", err)
+		}
+
+		if line != want {
+			t.Fatalf("Read() invalid value, expected <%q> and received <%q>", want, line)
+		}
+
+		if _, err = b.Write([]byte("second\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Ending the request must close the server's reader.
+	cancel()
+	deadline := time.Now().Add(5 * time.Second)
+	for m.closed.Load() != m.opened.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("ServeHTTP() did not close the reader when the request ended")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// writeLater writes bs shortly after a follow request starts waiting.
+func writeLater(bs string) (fn func(t *testing.T, b *streambuf.Buffer)) {
+	return func(t *testing.T, b *streambuf.Buffer) {
+		time.AfterFunc(20*time.Millisecond, func() {
+			_, _ = b.Write([]byte(bs))
+		})
+	}
+}
+
+// closeLater closes b shortly after a follow request starts waiting, ending the response.
+func closeLater(t *testing.T, b *streambuf.Buffer) {
+	time.AfterFunc(20*time.Millisecond, func() {
+		_ = b.Close()
+	})
+}
+
+// truncate discards the bytes of b before offset.
+func truncate(offset int64) (fn func(t *testing.T, b *streambuf.Buffer)) {
+	return func(t *testing.T, b *streambuf.Buffer) {
+		if err := b.Truncate(offset); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
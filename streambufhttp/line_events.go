@@ -0,0 +1,77 @@
+package streambufhttp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+var _ eventReader = &lineEvents{}
+
+// newLineEvents constructs an eventReader that yields each line of r.
+func newLineEvents(r io.ReadSeekCloser) (out *lineEvents) {
+	var l lineEvents
+	l.r = r
+	l.br = bufio.NewReader(r)
+	return &l
+}
+
+// lineEvents splits a byte stream into newline-terminated events.
+type lineEvents struct {
+	r  io.ReadSeekCloser
+	br *bufio.Reader
+
+	offset int64
+	// partial is set when the next line may have been partly discarded by
+	// retention, so it is skipped.
+	partial bool
+}
+
+// SeekOffset moves to offset, which should be the start of a line. An evicted
+// offset resumes at the low-water mark, skipping the line the mark falls within.
+func (l *lineEvents) SeekOffset(offset int64) (err error) {
+	l.offset, err = l.r.Seek(offset, io.SeekStart)
+	l.br.Reset(l.r)
+	if l.partial = errors.Is(err, streambuf.ErrOffsetEvicted); l.partial {
+		return nil
+	}
+
+	return err
+}
+
+// Next returns the next complete line without its line terminator.
+// A trailing partial line is only returned once more bytes complete it.
+// When retention discards lines before they are read, Next resumes at the
+// low-water mark.
+func (l *lineEvents) Next() (data []byte, id int64, err error) {
+	var line []byte
+	for {
+		switch line, err = l.br.ReadBytes('\n'); {
+		case errors.Is(err, streambuf.ErrOffsetEvicted):
+			if err = l.SeekOffset(l.offset); err != nil {
+				return nil, 0, err
+			}
+
+			continue
+		case err != nil:
+			return nil, 0, err
+		}
+
+		l.offset += int64(len(line))
+		if l.partial {
+			l.partial = false
+			continue
+		}
+
+		line = bytes.TrimSuffix(line[:len(line)-1], []byte("\r"))
+		return line, l.offset, nil
+	}
+}
+
+// Close closes the underlying reader.
+func (l *lineEvents) Close() (err error) {
+	return l.r.Close()
+}
@@ -0,0 +1,24 @@
+package streambufhttp
+
+// WithContentType sets the Content-Type of responses.
+// The default is application/octet-stream.
+func WithContentType(contentType string) (opt Option) {
+	return func(c *config) {
+		c.contentType = contentType
+	}
+}
+
+// WithETag sets a strong ETag identifying the served stream. Snapshot
+// responses qualify it with their size, such as "v1-11", so If-None-Match stops
+// matching once the stream grows. Because streams are append-only, bytes at an
+// offset never change, so clients may resume with Range and an If-Range of
+// either form until the stream is replaced. Without an ETag, requests carrying
+// If-Range receive the full stream.
+func WithETag(etag string) (opt Option) {
+	return func(c *config) {
+		c.etag = etag
+	}
+}
+
+// Option configures a Handler at construction.
+type Option func(c *config)
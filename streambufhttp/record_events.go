@@ -0,0 +1,104 @@
+package streambufhttp
+
+import (
+	"errors"
+	"io"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+var _ eventReader = &recordEvents{}
+
+// newRecordEvents constructs an eventReader that yields each record of rr,
+// a streaming reader of rb.
+func newRecordEvents(rb *streambuf.RecordBuffer, rr *streambuf.RecordReader) (out *recordEvents) {
+	var r recordEvents
+	r.rb = rb
+	r.rr = rr
+	return &r
+}
+
+// recordEvents yields one event per record.
+type recordEvents struct {
+	rb *streambuf.RecordBuffer
+	rr *streambuf.RecordReader
+}
+
+// SeekOffset moves to offset, which must be a record boundary. An offset past
+// the end, or one whose record header does not decode, returns
+// errInvalidLastEventID. Without record checksums only the declared length is
+// checked. An evicted offset resumes at the low-water mark when it is a record
+// boundary, and returns the *streambuf.OffsetEvictedError otherwise.
+func (r *recordEvents) SeekOffset(offset int64) (err error) {
+	var (
+		pos     int64
+		evicted error
+	)
+
+	if pos, err = r.rr.Seek(offset, io.SeekStart); errors.Is(err, streambuf.ErrOffsetEvicted) {
+		evicted = err
+	} else if err != nil {
+		return err
+	}
+
+	if err = r.checkBoundary(pos); errors.Is(err, errInvalidLastEventID) && evicted != nil {
+		return evicted
+	}
+
+	return err
+}
+
+// Next returns the next record.
+func (r *recordEvents) Next() (data []byte, id int64, err error) {
+	offset := r.rr.Offset()
+	switch data, err = r.rr.ReadRecord(); {
+	case errors.Is(err, streambuf.ErrCorruptRecord) && r.rr.Offset() == offset:
+		// A corrupt header cannot be skipped, so no further records can be read.
+		return nil, 0, io.ErrUnexpectedEOF
+	case err != nil:
+		return nil, 0, err
+	}
+
+	return data, r.rr.Offset(), nil
+}
+
+// Close closes the underlying record reader.
+func (r *recordEvents) Close() (err error) {
+	return r.rr.Close()
+}
+
+// checkBoundary decodes the record at offset with a non-streaming reader, so
+// the check never waits for future records. Records are written whole, so a
+// boundary before the end is always followed by a complete record.
+func (r *recordEvents) checkBoundary(offset int64) (err error) {
+	var (
+		check *streambuf.RecordReader
+		end   int64
+	)
+
+	if check, err = r.rb.Reader(); err != nil {
+		return err
+	}
+	defer check.Close()
+
+	if end, err = check.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+
+	switch {
+	case offset > end:
+		return errInvalidLastEventID
+	case offset == end:
+		return nil
+	}
+
+	if _, err = check.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	if _, err = check.ReadRecord(); err == io.EOF || errors.Is(err, streambuf.ErrCorruptRecord) {
+		return errInvalidLastEventID
+	}
+
+	return err
+}
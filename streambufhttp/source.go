@@ -0,0 +1,10 @@
+package streambufhttp
+
+import "io"
+
+// Source is the stream served by a Handler.
+// *streambuf.Buffer and *streambuf.FollowStream satisfy it.
+type Source interface {
+	Reader() (r io.ReadSeekCloser, err error)
+	StreamingReader() (r io.ReadSeekCloser, err error)
+}
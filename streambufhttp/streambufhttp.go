@@ -0,0 +1,6 @@
+package streambufhttp
+
+import "errors"
+
+// errInvalidLastEventID is returned for a Last-Event-ID that is not a stream offset.
+var errInvalidLastEventID = errors.New("invalid Last-Event-ID")
@@ -0,0 +1,36 @@
+package streambufhttp
+
+import "io"
+
+// newWindowReader constructs a reader that presents the bytes of r from low
+// onward as if they started at offset 0.
+func newWindowReader(r io.ReadSeeker, low int64) (out *windowReader) {
+	var w windowReader
+	w.r = r
+	w.low = low
+	return &w
+}
+
+// windowReader shifts offsets so a snapshot covers only the retained window.
+type windowReader struct {
+	r   io.ReadSeeker
+	low int64
+}
+
+// Read reads from the underlying reader.
+func (w *windowReader) Read(in []byte) (n int, err error) {
+	return w.r.Read(in)
+}
+
+// Seek seeks the underlying reader, translating offsets relative to the window.
+func (w *windowReader) Seek(offset int64, whence int) (pos int64, err error) {
+	if whence == io.SeekStart {
+		offset += w.low
+	}
+
+	if pos, err = w.r.Seek(offset, whence); err != nil {
+		return 0, err
+	}
+
+	return pos - w.low, nil
+}
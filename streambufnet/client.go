@@ -0,0 +1,70 @@
+package streambufnet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+)
+
+// NewClient constructs a Client for the Server at addr on network, such as
+// "tcp" or "unix".
+func NewClient(network, addr string, opts ...Option) (out *Client) {
+	var c Client
+	c.network = network
+	c.addr = addr
+	c.c = newConfig(opts)
+	return &c
+}
+
+// Client opens remote readers on a Server.
+type Client struct {
+	network string
+	addr    string
+
+	c *config
+}
+
+// Open connects to the Server and opens a reader on the stream registered as
+// name. The returned reader mirrors streambuf reader semantics: follow readers
+// block at the current end, other readers return io.EOF, and reads after
+// Close return streambuf.ErrIsClosed. ctx bounds only connecting and opening.
+func (c *Client) Open(ctx context.Context, name string, cfg ReaderConfig) (r io.ReadSeekCloser, err error) {
+	var (
+		conn net.Conn
+		resp response
+	)
+
+	if len(name) > maxNameLength {
+		return nil, ErrInvalidName
+	}
+
+	if conn, err = c.c.dialer.DialContext(ctx, c.network, c.addr); err != nil {
+		return nil, fmt.Errorf("dial server: %w", err)
+	}
+
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	req := cfg.request(name)
+	if err = req.writeTo(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if err = resp.readFrom(conn, nil); err == nil {
+		err = resp.err()
+	}
+
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if !stop() {
+		_ = conn.Close()
+		return nil, ctx.Err()
+	}
+
+	return newReader(conn), nil
+}
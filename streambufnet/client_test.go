@@ -0,0 +1,415 @@
+package streambufnet
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+func Test_Client_Open(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		network string
+		stream  string
+		cfg     ReaderConfig
+
+		want    string
+		wantErr error
+	}
+
+	tests := []testcase{
+		{
+			name:    "tcp from start",
+			network: "tcp",
+			stream:  "buffer",
+			want:    "hello world",
+		},
+		{
+			name:    "unix from start",
+			network: "unix",
+			stream:  "buffer",
+			want:    "hello world",
+		},
+		{
+			name:    "offset",
+			network: "tcp",
+			stream:  "buffer",
+			cfg:     ReaderConfig{Offset: 6},
+			want:    "world",
+		},
+		{
+			name:    "limit",
+			network: "tcp",
+			stream:  "buffer",
+			cfg:     ReaderConfig{Offset: 2, Limit: 5},
+			want:    "llo w",
+		},
+		{
+			name:    "read-only stream",
+			network: "unix",
+			stream:  "stream",
+			want:    "static",
+		},
+		{
+			name:    "not found",
+			network: "tcp",
+			stream:  "missing",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "follow not supported",
+			network: "tcp",
+			stream:  "stream",
+			cfg:     ReaderConfig{Follow: true},
+			wantErr: ErrFollowNotSupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				r   io.ReadSeekCloser
+				bs  []byte
+				err error
+			)
+
+			c, _ := startServer(t, tt.network)
+			if r, err = c.Open(context.Background(), tt.stream, tt.cfg); err != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Open() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+				}
+
+				return
+			}
+			t.Cleanup(func() { _ = r.Close() })
+
+			if tt.wantErr != nil {
+				t.Fatalf("Open() expected error <%v>, received <nil>", tt.wantErr)
+			}
+
+			if bs, err = io.ReadAll(r); err != nil {
+				t.Fatalf("ReadAll() unexpected error: %v", err)
+			}
+
+			if string(bs) != tt.want {
+				t.Fatalf("ReadAll() invalid value, expected <%q> and received <%q>", tt.want, bs)
+			}
+		})
+	}
+}
+
+func Test_reader_follow(t *testing.T) {
+	var (
+		r   io.ReadSeekCloser
+		err error
+	)
+
+	c, b := startServer(t, "tcp")
+	if r, err = c.Open(context.Background(), "buffer", ReaderConfig{Offset: 6, Follow: true}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+
+	if got := readN(t, r, 5); got != "world" {
+		t.Fatalf("Read() invalid value, expected <%q> and received <%q>", "world", got)
+	}
+
+	time.AfterFunc(20*time.Millisecond, func() { _, _ = b.Write([]byte("!")) })
+	if got := readN(t, r, 1); got != "!" {
+		t.Fatalf("Read() invalid value, expected <%q> and received <%q>", "!", got)
+	}
+
+	// Closing the remote Buffer ends a follow reader with EOF.
+	time.AfterFunc(20*time.Millisecond, func() { _ = b.Close() })
+	if _, err = r.Read(make([]byte, 8)); err != io.EOF {
+		t.Fatalf("Read() invalid error, expected <%v> and received <%v>", io.EOF, err)
+	}
+}
+
+func Test_reader_Seek(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		offset int64
+		whence int
+
+		wantPos int64
+		wantErr error
+		want    string
+	}
+
+	tests := []testcase{
+		{
+			name:    "start",
+			offset:  6,
+			whence:  io.SeekStart,
+			wantPos: 6,
+			want:    "world",
+		},
+		{
+			name:    "end",
+			offset:  -3,
+			whence:  io.SeekEnd,
+			wantPos: 8,
+			want:    "rld",
+		},
+		{
+			name:    "negative",
+			offset:  -1,
+			whence:  io.SeekStart,
+			wantPos: 0,
+			wantErr: streambuf.ErrNegativeIndex,
+			want:    "hello world",
+		},
+		{
+			name:    "invalid whence",
+			offset:  0,
+			whence:  9,
+			wantErr: streambuf.ErrInvalidWhence,
+			want:    "hello world",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				r   io.ReadSeekCloser
+				pos int64
+				bs  []byte
+				err error
+			)
+
+			c, _ := startServer(t, "tcp")
+			if r, err = c.Open(context.Background(), "buffer", ReaderConfig{}); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = r.Close() })
+
+			if pos, err = r.Seek(tt.offset, tt.whence); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Seek() invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+			}
+
+			if pos != tt.wantPos {
+				t.Fatalf("Seek() invalid position, expected <%d> and received <%d>", tt.wantPos, pos)
+			}
+
+			if bs, err = io.ReadAll(r); err != nil {
+				t.Fatal(err)
+			}
+
+			if string(bs) != tt.want {
+				t.Fatalf("ReadAll() invalid value, expected <%q> and received <%q>", tt.want, bs)
+			}
+		})
+	}
+}
+
+func Test_reader_Close(t *testing.T) {
+	var (
+		r   io.ReadSeekCloser
+		err error
+	)
+
+	c, _ := startServer(t, "tcp")
+	if r, err = c.Open(context.Background(), "buffer", ReaderConfig{Offset: 11, Follow: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, readErr := r.Read(make([]byte, 8))
+		done <- readErr
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if err = r.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	select {
+	case err = <-done:
+		if !errors.Is(err, streambuf.ErrIsClosed) {
+			t.Fatalf("Read() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Read() was not unblocked by Close()")
+	}
+
+	if _, err = r.Read(make([]byte, 8)); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Read() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+
+	if err = r.Close(); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Close() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+}
+
+func Test_Server_Close(t *testing.T) {
+	var (
+		ln  net.Listener
+		err error
+	)
+
+	if ln, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer()
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+
+	if err = s.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	if err = <-done; !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Serve() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+
+	if err = s.Handle("buffer", streambuf.NewMemory()); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Handle() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+}
+
+func Test_Server_Close_accept_race(t *testing.T) {
+	var err error
+
+	ln := newLateListener()
+	s := NewServer()
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ln) }()
+	<-ln.accepting
+
+	if err = s.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	if err = <-done; !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Serve() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+
+	if !ln.conn.closed.Load() {
+		t.Fatal("Serve() did not close a connection accepted during Close")
+	}
+}
+
+// newLateListener constructs a listener whose only connection is accepted
+// once the listener is closed, as when Accept races Close.
+func newLateListener() (out *lateListener) {
+	var l lateListener
+	l.accepting = make(chan struct{})
+	l.done = make(chan struct{})
+	l.conn = newTrackedConn()
+	return &l
+}
+
+// lateListener returns one connection after Close and then net.ErrClosed.
+type lateListener struct {
+	once sync.Once
+	// accepting is closed by the first Accept call.
+	accepting chan struct{}
+	done      chan struct{}
+	accepted  atomic.Bool
+
+	conn *trackedConn
+}
+
+func (l *lateListener) Accept() (conn net.Conn, err error) {
+	if l.accepted.Swap(true) {
+		return nil, net.ErrClosed
+	}
+
+	close(l.accepting)
+	<-l.done
+
+	return l.conn, nil
+}
+
+func (l *lateListener) Close() (err error) {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *lateListener) Addr() (addr net.Addr) {
+	return l.conn.LocalAddr()
+}
+
+// newTrackedConn returns one end of an in-memory connection that records
+// whether it has been closed.
+func newTrackedConn() (out *trackedConn) {
+	var c trackedConn
+	c.Conn, _ = net.Pipe()
+	return &c
+}
+
+// trackedConn records whether it has been closed.
+type trackedConn struct {
+	net.Conn
+
+	closed atomic.Bool
+}
+
+func (c *trackedConn) Close() (err error) {
+	c.closed.Store(true)
+	return c.Conn.Close()
+}
+
+// startServer serves a Buffer named "buffer" holding "hello world" and a
+// read-only Stream named "stream" on network until the test ends.
+func startServer(t *testing.T, network string) (c *Client, b *streambuf.Buffer) {
+	t.Helper()
+
+	var (
+		ln  net.Listener
+		err error
+	)
+
+	addr := "127.0.0.1:0"
+	if network == "unix" {
+		addr = filepath.Join(t.TempDir(), "streambuf.sock")
+	}
+
+	if ln, err = net.Listen(network, addr); err != nil {
+		t.Fatal(err)
+	}
+
+	b = streambuf.NewMemory()
+	t.Cleanup(func() { _ = b.Close() })
+	if _, err = b.Write([]byte("hello world")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewServer()
+	if err = s.Handle("buffer", b); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = s.Handle("stream", streambuf.NewMemoryStream([]byte("static"))); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() { _ = s.Serve(ln) }()
+	t.Cleanup(func() { _ = s.Close() })
+	return NewClient(network, ln.Addr().String()), b
+}
+
+// readN reads exactly n bytes from r.
+func readN(t *testing.T, r io.Reader, n int) (out string) {
+	t.Helper()
+
+	bs := make([]byte, n)
+	if _, err := io.ReadFull(r, bs); err != nil {
+		t.Fatalf("Read() unexpected error: %v", err)
+	}
+
+	return string(bs)
+}
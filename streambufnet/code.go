@@ -0,0 +1,82 @@
+package streambufnet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+const (
+	codeOK                 code = 0
+	codeEOF                code = 1
+	codeClosed             code = 2
+	codeNotFound           code = 3
+	codeOffsetEvicted      code = 4
+	codeInvalidWhence      code = 5
+	codeNegativeIndex      code = 6
+	codeFollowNotSupported code = 7
+	codeTruncated          code = 8
+	codeRotated            code = 9
+	codeProtocol           code = 10
+	codeOther              code = 255
+)
+
+// codeSentinels maps codes to the errors they carry, in matching priority.
+var codeSentinels = []struct {
+	c   code
+	err error
+}{
+	{codeEOF, io.EOF},
+	{codeClosed, streambuf.ErrIsClosed},
+	{codeNotFound, ErrNotFound},
+	{codeOffsetEvicted, streambuf.ErrOffsetEvicted},
+	{codeInvalidWhence, streambuf.ErrInvalidWhence},
+	{codeNegativeIndex, streambuf.ErrNegativeIndex},
+	{codeFollowNotSupported, ErrFollowNotSupported},
+	{codeTruncated, streambuf.ErrTruncated},
+	{codeRotated, streambuf.ErrRotated},
+	{codeProtocol, ErrProtocol},
+}
+
+// codeOf returns the wire code for err.
+func codeOf(err error) (c code) {
+	if err == nil {
+		return codeOK
+	}
+
+	for _, s := range codeSentinels {
+		if errors.Is(err, s.err) {
+			return s.c
+		}
+	}
+
+	return codeOther
+}
+
+// code identifies the outcome of a request on the wire.
+type code uint16
+
+// err rebuilds the error for c, keeping msg when it adds detail.
+func (c code) err(msg string) (err error) {
+	switch c {
+	case codeOK:
+		return nil
+	case codeEOF:
+		// io.Copy and io.ReadAll require an unwrapped EOF.
+		return io.EOF
+	}
+
+	for _, s := range codeSentinels {
+		switch {
+		case s.c != c:
+		case msg == "" || msg == s.err.Error():
+			return s.err
+		default:
+			return fmt.Errorf("%w: %s", s.err, msg)
+		}
+	}
+
+	return fmt.Errorf("remote: %s", msg)
+}
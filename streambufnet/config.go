@@ -0,0 +1,24 @@
+package streambufnet
+
+import (
+	"log/slog"
+	"net"
+)
+
+// newConfig applies opts over a discarding logger and a zero net.Dialer.
+func newConfig(opts []Option) (out *config) {
+	var c config
+	c.logger = slog.New(slog.DiscardHandler)
+	c.dialer = &net.Dialer{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c
+}
+
+// config holds the settings for a Server or Client.
+type config struct {
+	logger *slog.Logger
+	dialer *net.Dialer
+}
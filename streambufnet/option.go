@@ -0,0 +1,28 @@
+package streambufnet
+
+import (
+	"log/slog"
+	"net"
+)
+
+// WithLogger sets the logger a Server uses to report failed connections.
+// A nil logger is ignored.
+func WithLogger(logger *slog.Logger) (opt Option) {
+	return func(c *config) {
+		if logger == nil {
+			return
+		}
+
+		c.logger = logger
+	}
+}
+
+// WithDialer sets the dialer a Client uses to reach the Server.
+func WithDialer(dialer *net.Dialer) (opt Option) {
+	return func(c *config) {
+		c.dialer = dialer
+	}
+}
+
+// Option configures a Server or Client at construction.
+type Option func(c *config)
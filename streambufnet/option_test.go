@@ -0,0 +1,44 @@
+package streambufnet
+
+import (
+	"net"
+	"testing"
+)
+
+func Test_Option(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		opts []Option
+	}
+
+	tests := []testcase{
+		{
+			name: "defaults",
+		},
+		{
+			name: "dialer",
+			opts: []Option{WithDialer(&net.Dialer{})},
+		},
+		{
+			name: "nil logger keeps the default",
+			opts: []Option{WithLogger(nil)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfig(tt.opts)
+			if c.logger == nil {
+				t.Fatal("newConfig() invalid logger, expected a default and received <nil>")
+			}
+
+			// Logging must not panic.
+			c.logger.Info("streambufnet: test")
+
+			if c.dialer == nil {
+				t.Fatal("newConfig() invalid dialer, expected a dialer and received <nil>")
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package streambufnet
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// newReader constructs a remote reader over an opened connection.
+func newReader(conn net.Conn) (out *reader) {
+	var r reader
+	r.conn = conn
+	return &r
+}
+
+// reader is the client side of a remote reader.
+type reader struct {
+	// mux serializes requests, since the protocol is request/response.
+	mux sync.Mutex
+
+	conn net.Conn
+
+	closed atomic.Bool
+}
+
+// Read requests up to len(in) bytes from the remote reader.
+// Follow readers block until bytes are written or the remote stream closes.
+// A zero-length read returns (0, nil) immediately.
+// Read returns streambuf.ErrIsClosed after Close, including for a pending Read.
+func (r *reader) Read(in []byte) (n int, err error) {
+	var resp response
+	if len(in) == 0 {
+		return 0, nil
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	req := request{op: opRead, max: uint32(min(len(in), maxReadSize))}
+	if err = r.roundTrip(&req, &resp, in); err != nil {
+		return 0, err
+	}
+
+	if err = resp.err(); err != nil {
+		return 0, err
+	}
+
+	// Payloads that fit are decoded directly into in.
+	return copy(in, resp.payload), nil
+}
+
+// Seek moves the remote reader using whence semantics and returns the new
+// position. Like the local reader, a clamped position is returned together
+// with streambuf.ErrNegativeIndex or an offset evicted error.
+func (r *reader) Seek(offset int64, whence int) (pos int64, err error) {
+	var resp response
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	req := request{op: opSeek, offset: offset, whence: byte(whence)}
+	if err = r.roundTrip(&req, &resp, nil); err != nil {
+		return 0, err
+	}
+
+	return resp.value, resp.err()
+}
+
+// Close closes the connection, which closes the remote reader and unblocks a
+// pending Read.
+func (r *reader) Close() (err error) {
+	if !r.closed.CompareAndSwap(false, true) {
+		return streambuf.ErrIsClosed
+	}
+
+	if err = r.conn.Close(); err != nil {
+		return fmt.Errorf("close connection: %w", err)
+	}
+
+	return nil
+}
+
+// roundTrip sends req and reads its response, decoding the payload into buf
+// when it fits.
+func (r *reader) roundTrip(req *request, resp *response, buf []byte) (err error) {
+	if r.closed.Load() {
+		return streambuf.ErrIsClosed
+	}
+
+	if err = req.writeTo(r.conn); err == nil {
+		err = resp.readFrom(r.conn, buf)
+	}
+
+	if err != nil && r.closed.Load() {
+		return streambuf.ErrIsClosed
+	}
+
+	return err
+}
@@ -0,0 +1,27 @@
+package streambufnet
+
+// ReaderConfig describes a remote reader opened by Client.Open.
+type ReaderConfig struct {
+	// Offset is the absolute offset the reader starts at.
+	Offset int64
+	// Follow makes the reader wait for future writes at the current end, like
+	// a StreamingReader, instead of returning io.EOF.
+	Follow bool
+	// Limit bounds the total bytes the reader returns before io.EOF.
+	// Zero means no limit.
+	Limit int64
+}
+
+// request returns the open request for c.
+func (c ReaderConfig) request(name string) (out request) {
+	out.op = opOpen
+	out.name = name
+	out.offset = c.Offset
+	out.limit = c.Limit
+	out.mode = modeEOF
+	if c.Follow {
+		out.mode = modeFollow
+	}
+
+	return out
+}
@@ -0,0 +1,115 @@
+package streambufnet
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	opOpen byte = 'O'
+	opRead byte = 'R'
+	opSeek byte = 'S'
+
+	modeEOF    byte = 0
+	modeFollow byte = 1
+
+	// maxNameLength bounds stream names, which are sent with a uint16 length.
+	maxNameLength = 1<<16 - 1
+)
+
+// request is a single client message.
+type request struct {
+	op byte
+
+	// name, mode, and limit are set by open.
+	name  string
+	mode  byte
+	limit int64
+
+	// offset is the start offset for open and the seek offset for seek.
+	offset int64
+	// whence is set by seek.
+	whence byte
+
+	// max is the maximum read length.
+	max uint32
+}
+
+// writeTo encodes r to w in a single write.
+func (r *request) writeTo(w io.Writer) (err error) {
+	bs := []byte{r.op}
+	switch r.op {
+	case opOpen:
+		bs = binary.BigEndian.AppendUint16(bs, uint16(len(r.name)))
+		bs = append(bs, r.name...)
+		bs = binary.BigEndian.AppendUint64(bs, uint64(r.offset))
+		bs = append(bs, r.mode)
+		bs = binary.BigEndian.AppendUint64(bs, uint64(r.limit))
+	case opRead:
+		bs = binary.BigEndian.AppendUint32(bs, r.max)
+	case opSeek:
+		bs = binary.BigEndian.AppendUint64(bs, uint64(r.offset))
+		bs = append(bs, r.whence)
+	}
+
+	if _, err = w.Write(bs); err != nil {
+		return fmt.Errorf("write request: %w", err)
+	}
+
+	return nil
+}
+
+// readFrom decodes the next request from br into r.
+func (r *request) readFrom(br *bufio.Reader) (err error) {
+	var bs [17]byte
+	if r.op, err = br.ReadByte(); err != nil {
+		return fmt.Errorf("read request: %w", err)
+	}
+
+	switch r.op {
+	case opOpen:
+		return r.readOpen(br)
+	case opRead:
+		if _, err = io.ReadFull(br, bs[:4]); err != nil {
+			return fmt.Errorf("read request: %w", err)
+		}
+
+		r.max = binary.BigEndian.Uint32(bs[:4])
+		return nil
+	case opSeek:
+		if _, err = io.ReadFull(br, bs[:9]); err != nil {
+			return fmt.Errorf("read request: %w", err)
+		}
+
+		r.offset = int64(binary.BigEndian.Uint64(bs[:8]))
+		r.whence = bs[8]
+		return nil
+	default:
+		return ErrProtocol
+	}
+}
+
+// readOpen decodes the body of an open request.
+func (r *request) readOpen(br *bufio.Reader) (err error) {
+	var bs [17]byte
+	if _, err = io.ReadFull(br, bs[:2]); err != nil {
+		return fmt.Errorf("read request: %w", err)
+	}
+
+	name := make([]byte, binary.BigEndian.Uint16(bs[:2]))
+	if _, err = io.ReadFull(br, name); err != nil {
+		return fmt.Errorf("read request: %w", err)
+	}
+
+	if _, err = io.ReadFull(br, bs[:17]); err != nil {
+		return fmt.Errorf("read request: %w", err)
+	}
+
+	r.name = string(name)
+	r.offset = int64(binary.BigEndian.Uint64(bs[:8]))
+	r.mode = bs[8]
+	r.limit = int64(binary.BigEndian.Uint64(bs[9:17]))
+	return nil
+}
@@ -0,0 +1,75 @@
+package streambufnet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// responseHeaderSize is the size of the code, value, and length fields.
+	responseHeaderSize = 14
+	// maxReadSize bounds the payload of a single read response.
+	maxReadSize = 1 << 20
+)
+
+// newErrorResponse constructs a response reporting err.
+func newErrorResponse(value int64, err error) (out response) {
+	out.code = codeOf(err)
+	out.value = value
+	out.payload = []byte(err.Error())
+	return out
+}
+
+// response is a single server message.
+type response struct {
+	code    code
+	value   int64
+	payload []byte
+}
+
+// err returns the error carried by the response, or nil.
+func (r *response) err() (err error) {
+	return r.code.err(string(r.payload))
+}
+
+// writeTo encodes r to w in a single write.
+func (r *response) writeTo(w io.Writer) (err error) {
+	bs := make([]byte, 0, responseHeaderSize+len(r.payload))
+	bs = binary.BigEndian.AppendUint16(bs, uint16(r.code))
+	bs = binary.BigEndian.AppendUint64(bs, uint64(r.value))
+	bs = binary.BigEndian.AppendUint32(bs, uint32(len(r.payload)))
+	bs = append(bs, r.payload...)
+	if _, err = w.Write(bs); err != nil {
+		return fmt.Errorf("write response: %w", err)
+	}
+
+	return nil
+}
+
+// readFrom decodes the next response from rd into r. The payload is read
+// into buf when it fits, so read responses avoid an extra copy.
+func (r *response) readFrom(rd io.Reader, buf []byte) (err error) {
+	var bs [responseHeaderSize]byte
+	if _, err = io.ReadFull(rd, bs[:]); err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	r.code = code(binary.BigEndian.Uint16(bs[:2]))
+	r.value = int64(binary.BigEndian.Uint64(bs[2:10]))
+	length := binary.BigEndian.Uint32(bs[10:14])
+	if length > maxReadSize {
+		return ErrProtocol
+	}
+
+	if r.code != codeOK || int(length) > len(buf) {
+		buf = make([]byte, length)
+	}
+
+	r.payload = buf[:length]
+	if _, err = io.ReadFull(rd, r.payload); err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	return nil
+}
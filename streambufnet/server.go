@@ -0,0 +1,182 @@
+package streambufnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// NewServer constructs a Server with no registered streams.
+func NewServer(opts ...Option) (out *Server) {
+	var s Server
+	s.c = newConfig(opts)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.sources = make(map[string]Source)
+	s.listeners = make(map[net.Listener]struct{})
+	return &s
+}
+
+// Server serves named streams to remote readers, one reader per connection.
+type Server struct {
+	mux sync.RWMutex
+	wg  sync.WaitGroup
+
+	c *config
+
+	// ctx is canceled on Close to end every session.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	sources   map[string]Source
+	listeners map[net.Listener]struct{}
+
+	closed bool
+}
+
+// Handle registers src under name, replacing any previous registration.
+// Readers already open on a replaced source are unaffected.
+func (s *Server) Handle(name string, src Source) (err error) {
+	if name == "" || len(name) > maxNameLength {
+		return ErrInvalidName
+	}
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return streambuf.ErrIsClosed
+	}
+
+	s.sources[name] = src
+	return nil
+}
+
+// Remove unregisters name. Readers already open on it are unaffected.
+func (s *Server) Remove(name string) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	delete(s.sources, name)
+}
+
+// Serve accepts connections on ln until the Server is closed, and then
+// returns streambuf.ErrIsClosed. ln is closed when Serve returns.
+// Any stream listener works, including TCP and Unix sockets.
+func (s *Server) Serve(ln net.Listener) (err error) {
+	if err = s.track(ln); err != nil {
+		_ = ln.Close()
+		return err
+	}
+	defer s.untrack(ln)
+
+	var conn net.Conn
+	for {
+		if conn, err = ln.Accept(); err != nil {
+			break
+		}
+
+		if !s.startSession() {
+			_ = conn.Close()
+			break
+		}
+
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+		}()
+	}
+
+	if s.ctx.Err() != nil {
+		return streambuf.ErrIsClosed
+	}
+
+	return fmt.Errorf("accept connection: %w", err)
+}
+
+// Close stops accepting connections, closes every open remote reader, and
+// waits for their sessions to end. Registered streams are not closed.
+func (s *Server) Close() (err error) {
+	s.mux.Lock()
+	if s.closed {
+		s.mux.Unlock()
+		return streambuf.ErrIsClosed
+	}
+
+	s.closed = true
+	s.cancel()
+	for ln := range s.listeners {
+		_ = ln.Close()
+	}
+
+	s.mux.Unlock()
+	s.wg.Wait()
+	return nil
+}
+
+// open opens a reader described by an open request.
+func (s *Server) open(req request) (r io.ReadSeekCloser, err error) {
+	var src Source
+	s.mux.RLock()
+	src = s.sources[req.name]
+	s.mux.RUnlock()
+
+	switch {
+	case src == nil:
+		return nil, ErrNotFound
+	case req.mode == modeEOF:
+		return src.Reader()
+	case req.mode != modeFollow:
+		return nil, ErrProtocol
+	}
+
+	if fs, ok := src.(followSource); ok {
+		return fs.StreamingReader()
+	}
+
+	return nil, ErrFollowNotSupported
+}
+
+// serveConn runs a session and logs why it ended.
+func (s *Server) serveConn(conn net.Conn) {
+	ss := newSession(s.ctx, conn, s)
+	if err := ss.run(); err != nil && !errors.Is(err, streambuf.ErrIsClosed) && !errors.Is(err, io.EOF) {
+		s.c.logger.Warn("streambufnet: session ended", "remote", conn.RemoteAddr().String(), "error", err)
+	}
+}
+
+// startSession counts a new session for Close to wait on, unless the Server
+// is already closed. Checking closed under the same lock as Close keeps the
+// count from growing once Close has started waiting.
+func (s *Server) startSession() (ok bool) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return false
+	}
+
+	s.wg.Add(1)
+	return true
+}
+
+// track registers ln so Close can stop it.
+func (s *Server) track(ln net.Listener) (err error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return streambuf.ErrIsClosed
+	}
+
+	s.listeners[ln] = struct{}{}
+	return nil
+}
+
+// untrack closes and forgets ln.
+func (s *Server) untrack(ln net.Listener) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	_ = ln.Close()
+	delete(s.listeners, ln)
+}
@@ -0,0 +1,178 @@
+package streambufnet
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// newSession constructs the server side of one connection.
+func newSession(ctx context.Context, conn net.Conn, s *Server) (out *session) {
+	var ss session
+	ss.ctx, ss.cancel = context.WithCancel(ctx)
+	ss.conn = conn
+	ss.br = bufio.NewReader(conn)
+	ss.s = s
+	return &ss
+}
+
+// session serves one remote reader.
+type session struct {
+	// ctx is canceled when the Server closes or the client disconnects,
+	// which ends a pending follow read.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	conn net.Conn
+	br   *bufio.Reader
+	s    *Server
+
+	r     io.ReadSeekCloser
+	limit int64
+	// delivered counts bytes returned, for the limit.
+	delivered int64
+	buf       []byte
+}
+
+// run opens the reader and answers requests until the connection ends.
+func (ss *session) run() (err error) {
+	defer ss.cancel()
+	defer ss.conn.Close()
+	stop := context.AfterFunc(ss.ctx, func() { _ = ss.conn.Close() })
+	defer stop()
+
+	if err = ss.open(); err != nil {
+		return err
+	}
+	defer ss.r.Close()
+
+	reqs := make(chan request)
+	go ss.readRequests(reqs)
+
+	var resp response
+	for req := range reqs {
+		switch req.op {
+		case opRead:
+			resp = ss.read(req.max)
+		case opSeek:
+			resp = ss.seek(req.offset, int(req.whence))
+		default:
+			resp = newErrorResponse(0, ErrProtocol)
+		}
+
+		if err = resp.writeTo(ss.conn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// open handles the open request that must start every connection.
+func (ss *session) open() (err error) {
+	var (
+		req request
+		pos int64
+	)
+
+	if err = req.readFrom(ss.br); err != nil {
+		return err
+	}
+
+	if req.op != opOpen {
+		resp := newErrorResponse(0, ErrProtocol)
+		_ = resp.writeTo(ss.conn)
+		return ErrProtocol
+	}
+
+	if ss.r, err = ss.s.open(req); err != nil {
+		resp := newErrorResponse(0, err)
+		_ = resp.writeTo(ss.conn)
+		return err
+	}
+
+	if pos, err = ss.r.Seek(req.offset, io.SeekStart); err != nil {
+		resp := newErrorResponse(pos, err)
+		_ = resp.writeTo(ss.conn)
+		_ = ss.r.Close()
+		return err
+	}
+
+	ss.limit = req.limit
+	resp := response{code: codeOK, value: pos}
+	return resp.writeTo(ss.conn)
+}
+
+// readRequests decodes requests until the connection fails, then cancels the
+// session so a pending read returns.
+func (ss *session) readRequests(reqs chan<- request) {
+	defer close(reqs)
+	defer ss.cancel()
+
+	var req request
+	for {
+		if err := req.readFrom(ss.br); err != nil {
+			return
+		}
+
+		select {
+		case reqs <- req:
+		case <-ss.ctx.Done():
+			return
+		}
+	}
+}
+
+// read answers a read request, honoring the byte limit.
+func (ss *session) read(max uint32) (resp response) {
+	var (
+		n   int
+		err error
+	)
+
+	size := int64(min(max, maxReadSize))
+	if ss.limit > 0 {
+		if size = min(size, ss.limit-ss.delivered); size == 0 {
+			return newErrorResponse(0, io.EOF)
+		}
+	}
+
+	if int64(len(ss.buf)) < size {
+		ss.buf = make([]byte, size)
+	}
+
+	if n, err = ss.readContext(ss.buf[:size]); err != nil {
+		return newErrorResponse(0, err)
+	}
+
+	ss.delivered += int64(n)
+	resp.payload = ss.buf[:n]
+	return resp
+}
+
+// readContext reads from the reader, ending early when the session ends.
+func (ss *session) readContext(in []byte) (n int, err error) {
+	if cr, ok := ss.r.(streambuf.ContextReader); ok {
+		return cr.ReadContext(ss.ctx, in)
+	}
+
+	return ss.r.Read(in)
+}
+
+// seek answers a seek request with the resulting position.
+func (ss *session) seek(offset int64, whence int) (resp response) {
+	var (
+		pos int64
+		err error
+	)
+
+	if pos, err = ss.r.Seek(offset, whence); err != nil {
+		return newErrorResponse(pos, err)
+	}
+
+	resp.value = pos
+	return resp
+}
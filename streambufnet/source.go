@@ -0,0 +1,14 @@
+package streambufnet
+
+import "io"
+
+// Source is a stream a Server can open readers on.
+// *streambuf.Buffer, *streambuf.Stream, and *streambuf.FollowStream satisfy it.
+type Source interface {
+	Reader() (r io.ReadSeekCloser, err error)
+}
+
+// followSource is a Source that supports follow-mode readers.
+type followSource interface {
+	StreamingReader() (r io.ReadSeekCloser, err error)
+}
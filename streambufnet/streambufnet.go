@@ -0,0 +1,44 @@
+// Package streambufnet serves named streambuf streams to remote readers over
+// stream sockets such as TCP and Unix sockets.
+//
+// # Protocol
+//
+// Every connection carries one reader and is strictly request/response: the
+// client sends a request and waits for its response before sending the next.
+// All integers are big-endian.
+//
+// Requests start with a one-byte op:
+//
+//   - 'O' open, which must be the first request: a uint16 name length, the
+//     name, an int64 start offset, a mode byte (0 returns EOF at the current
+//     end, 1 follows future writes), and an int64 byte limit (0 for none).
+//   - 'R' read: a uint32 maximum length. The server returns at most 1 MiB.
+//   - 'S' seek: an int64 offset and a whence byte (0 start, 1 current, 2 end).
+//
+// Every response has the same shape: a uint16 code, an int64 value, a uint32
+// payload length, and the payload. For open and seek the value is the reader
+// position; for read the payload holds the bytes read. A non-zero code is an
+// error and the payload holds its message:
+//
+//	0 ok, 1 EOF, 2 closed, 3 not found, 4 offset evicted, 5 invalid whence,
+//	6 negative index, 7 follow not supported, 8 truncated, 9 rotated,
+//	10 protocol error, 255 other
+//
+// A seek may return a clamped position together with an error code, matching
+// the semantics of the streambuf reader. Closing the connection closes the reader.
+package streambufnet
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when opening a reader for an unregistered name.
+	ErrNotFound = errors.New("stream not found")
+	// ErrFollowNotSupported is returned when opening a follow reader for a
+	// source without a StreamingReader.
+	ErrFollowNotSupported = errors.New("source does not support follow mode")
+	// ErrInvalidName is returned when registering an empty name or one longer
+	// than 65535 bytes.
+	ErrInvalidName = errors.New("invalid stream name")
+	// ErrProtocol is returned when a peer sends a malformed message.
+	ErrProtocol = errors.New("streambufnet protocol error")
+)
@@ -0,0 +1,45 @@
+package streambufrepl
+
+import (
+	"log/slog"
+	"net"
+	"time"
+)
+
+const (
+	// DefaultHeartbeatInterval is the heartbeat interval used when
+	// WithHeartbeatInterval is not set.
+	DefaultHeartbeatInterval = time.Second
+	// DefaultReconnectDelay is the delay between follower connection attempts
+	// used when WithReconnectDelay is not set.
+	DefaultReconnectDelay = time.Second
+)
+
+// newConfig applies opts over the default heartbeat, reconnect delay, logger,
+// and dialer.
+func newConfig(opts []Option) (out *config) {
+	var c config
+	c.heartbeat = DefaultHeartbeatInterval
+	c.reconnectDelay = DefaultReconnectDelay
+	c.logger = slog.New(slog.DiscardHandler)
+	c.dialer = &net.Dialer{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c
+}
+
+// config holds the replication settings shared by a Leader and its Followers.
+type config struct {
+	heartbeat      time.Duration
+	reconnectDelay time.Duration
+
+	logger *slog.Logger
+	dialer *net.Dialer
+}
+
+// timeout returns how long a follower waits for a frame before reconnecting.
+func (c *config) timeout() (d time.Duration) {
+	return 3 * c.heartbeat
+}
@@ -0,0 +1,136 @@
+package streambufrepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// NewFollower starts replicating the leader at addr into b. Replication
+// resumes from b's size, so a file Buffer reopened after a restart continues
+// where it stopped. The Follower must be the only writer to b; readers of b
+// observe replicated bytes like readers of the leader.
+func NewFollower(b *streambuf.Buffer, addr string, opts ...Option) (out *Follower) {
+	var f Follower
+	f.b = b
+	f.addr = addr
+	f.c = newConfig(opts)
+	f.ctx, f.cancel = context.WithCancel(context.Background())
+	f.done = make(chan struct{})
+	go f.run()
+	return &f
+}
+
+// Follower mirrors a leader's Buffer into a local Buffer, reconnecting after
+// disconnects.
+type Follower struct {
+	b    *streambuf.Buffer
+	addr string
+	c    *config
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// leaderSize is the leader's size from the latest heartbeat.
+	leaderSize atomic.Int64
+}
+
+// LeaderSize returns the leader's stream size as of its latest heartbeat.
+// Subtracting the local Buffer size gives the replication lag in bytes.
+func (f *Follower) LeaderSize() (n int64) {
+	return f.leaderSize.Load()
+}
+
+// Close stops replication and waits for the connection to close.
+// The local Buffer stays open.
+func (f *Follower) Close() (err error) {
+	if f.ctx.Err() != nil {
+		return streambuf.ErrIsClosed
+	}
+
+	f.cancel()
+	<-f.done
+	return nil
+}
+
+// run replicates until the Follower or its Buffer is closed.
+func (f *Follower) run() {
+	defer close(f.done)
+
+	var err error
+	for {
+		switch err = f.replicate(); {
+		case f.ctx.Err() != nil:
+			return
+		case errors.Is(err, streambuf.ErrIsClosed):
+			f.c.logger.Warn("streambufrepl: local buffer closed, stopping follower", "leader", f.addr)
+			return
+		default:
+			f.c.logger.Warn("streambufrepl: replication interrupted", "leader", f.addr, "error", err)
+		}
+
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-time.After(f.c.reconnectDelay):
+		}
+	}
+}
+
+// replicate runs a single connection, appending data frames to the Buffer.
+func (f *Follower) replicate() (err error) {
+	var (
+		offset int64
+		conn   net.Conn
+	)
+
+	if offset, err = f.b.Size(); err != nil {
+		return err
+	}
+
+	if conn, err = f.c.dialer.DialContext(f.ctx, "tcp", f.addr); err != nil {
+		return fmt.Errorf("dial leader: %w", err)
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(f.ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	if err = writeSubscribe(conn, offset); err != nil {
+		return err
+	}
+
+	return f.receive(conn)
+}
+
+// receive applies frames from conn until it fails or goes quiet for longer
+// than the follower timeout.
+func (f *Follower) receive(conn net.Conn) (err error) {
+	var fr frame
+	for {
+		if err = conn.SetReadDeadline(time.Now().Add(f.c.timeout())); err != nil {
+			return fmt.Errorf("set read deadline: %w", err)
+		}
+
+		if err = fr.readFrom(conn); err != nil {
+			return err
+		}
+
+		switch fr.kind {
+		case kindData:
+			if _, err = f.b.Write(fr.payload); err != nil {
+				return err
+			}
+		case kindHeartbeat:
+			f.leaderSize.Store(fr.size)
+		case kindError:
+			return fmt.Errorf("leader: %s", fr.payload)
+		}
+	}
+}
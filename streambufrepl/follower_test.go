@@ -0,0 +1,169 @@
+package streambufrepl
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+func Test_Follower(t *testing.T) {
+	var (
+		r   io.ReadSeekCloser
+		err error
+	)
+
+	leader := streambuf.NewMemory()
+	t.Cleanup(func() { _ = leader.Close() })
+	if _, err = leader.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := startLeader(t, leader)
+
+	local := streambuf.NewMemory()
+	t.Cleanup(func() { _ = local.Close() })
+
+	f := NewFollower(local, addr, WithHeartbeatInterval(50*time.Millisecond), WithReconnectDelay(10*time.Millisecond))
+	t.Cleanup(func() { _ = f.Close() })
+
+	if r, err = local.StreamingReader(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+
+	if got := readN(t, r, 5); got != "hello" {
+		t.Fatalf("Read() invalid value, expected <%q> and received <%q>", "hello", got)
+	}
+
+	if _, err = leader.Write([]byte(" world")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readN(t, r, 6); got != " world" {
+		t.Fatalf("Read() invalid value, expected <%q> and received <%q>", " world", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for f.LeaderSize() != 11 {
+		if time.Now().After(deadline) {
+			t.Fatalf("LeaderSize() invalid value, expected <11> and received <%d>", f.LeaderSize())
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func Test_Follower_reconnect(t *testing.T) {
+	var (
+		ln  net.Listener
+		r   io.ReadSeekCloser
+		err error
+	)
+
+	leader := streambuf.NewMemory()
+	t.Cleanup(func() { _ = leader.Close() })
+	if _, err = leader.Write([]byte("first ")); err != nil {
+		t.Fatal(err)
+	}
+
+	if ln, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := ln.Addr().String()
+	l := NewLeader(leader, WithHeartbeatInterval(50*time.Millisecond))
+	go func() { _ = l.Serve(ln) }()
+
+	local := streambuf.NewMemory()
+	t.Cleanup(func() { _ = local.Close() })
+
+	f := NewFollower(local, addr, WithHeartbeatInterval(50*time.Millisecond), WithReconnectDelay(10*time.Millisecond))
+	t.Cleanup(func() { _ = f.Close() })
+
+	if r, err = local.StreamingReader(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+
+	if got := readN(t, r, 6); got != "first " {
+		t.Fatalf("Read() invalid value, expected <%q> and received <%q>", "first ", got)
+	}
+
+	// Bytes written while the leader is down arrive after the follower reconnects.
+	if err = l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = leader.Write([]byte("second")); err != nil {
+		t.Fatal(err)
+	}
+
+	startLeaderAt(t, leader, addr)
+
+	if got := readN(t, r, 6); got != "second" {
+		t.Fatalf("Read() invalid value, expected <%q> and received <%q>", "second", got)
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	var size int64
+	if size, err = local.Size(); err != nil || size != 12 {
+		t.Fatalf("Size() invalid value, expected <12> and received <%d> (%v)", size, err)
+	}
+}
+
+// readN reads exactly n bytes from r, failing the test after five seconds.
+func readN(t *testing.T, r io.Reader, n int) (out string) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		count int
+		err   error
+	)
+
+	bs := make([]byte, n)
+	for read := 0; read < n; read += count {
+		if count, err = r.(streambuf.ContextReader).ReadContext(ctx, bs[read:]); err != nil {
+			t.Fatalf("Read() unexpected error: %v", err)
+		}
+	}
+
+	return string(bs)
+}
+
+func Test_Follower_LeaderSize_under_load(t *testing.T) {
+	leader := streambuf.NewMemory()
+	t.Cleanup(func() { _ = leader.Close() })
+
+	addr := startLeader(t, leader)
+
+	local := streambuf.NewMemory()
+	t.Cleanup(func() { _ = local.Close() })
+
+	f := NewFollower(local, addr, WithHeartbeatInterval(50*time.Millisecond), WithReconnectDelay(10*time.Millisecond))
+	t.Cleanup(func() { _ = f.Close() })
+
+	// Writes never pause for a heartbeat interval, so heartbeats must be sent
+	// alongside data for LeaderSize to advance.
+	deadline := time.Now().Add(5 * time.Second)
+	for f.LeaderSize() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("LeaderSize() invalid value, expected a heartbeat while data is flowing")
+		}
+
+		if _, err := leader.Write(make([]byte, 64)); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,112 @@
+package streambufrepl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const (
+	// magic starts every subscribe message and identifies the protocol version.
+	magic = "SBR1"
+
+	kindData      byte = 'D'
+	kindHeartbeat byte = 'H'
+	kindError     byte = 'E'
+
+	// maxFrameSize bounds data and error frames read from the network.
+	maxFrameSize = 1 << 20
+)
+
+// frame is a single message sent from leader to follower.
+type frame struct {
+	kind byte
+	// payload holds data bytes or an error message.
+	payload []byte
+	// size is the leader's stream size carried by heartbeats.
+	size int64
+}
+
+// writeTo encodes f to w in a single write.
+func (f *frame) writeTo(w io.Writer) (err error) {
+	var bs []byte
+	bs = append(bs, f.kind)
+	switch f.kind {
+	case kindHeartbeat:
+		bs = binary.BigEndian.AppendUint64(bs, uint64(f.size))
+	default:
+		bs = binary.BigEndian.AppendUint32(bs, uint32(len(f.payload)))
+		bs = append(bs, f.payload...)
+	}
+
+	if _, err = w.Write(bs); err != nil {
+		return fmt.Errorf("write frame: %w", err)
+	}
+
+	return nil
+}
+
+// readFrom decodes the next frame from r into f, reusing its payload buffer.
+func (f *frame) readFrom(r io.Reader) (err error) {
+	var header [9]byte
+	if _, err = io.ReadFull(r, header[:1]); err != nil {
+		return fmt.Errorf("read frame: %w", err)
+	}
+
+	switch f.kind = header[0]; f.kind {
+	case kindHeartbeat:
+		if _, err = io.ReadFull(r, header[1:9]); err != nil {
+			return fmt.Errorf("read heartbeat: %w", err)
+		}
+
+		f.size = int64(binary.BigEndian.Uint64(header[1:9]))
+		return nil
+	case kindData, kindError:
+	default:
+		return ErrInvalidFrame
+	}
+
+	if _, err = io.ReadFull(r, header[1:5]); err != nil {
+		return fmt.Errorf("read frame length: %w", err)
+	}
+
+	length := binary.BigEndian.Uint32(header[1:5])
+	if length > maxFrameSize {
+		return ErrInvalidFrame
+	}
+
+	f.payload = append(f.payload[:0], make([]byte, length)...)
+	if _, err = io.ReadFull(r, f.payload); err != nil {
+		return fmt.Errorf("read frame payload: %w", err)
+	}
+
+	return nil
+}
+
+// writeSubscribe sends the subscribe message for offset.
+func writeSubscribe(w io.Writer, offset int64) (err error) {
+	bs := binary.BigEndian.AppendUint64([]byte(magic), uint64(offset))
+	if _, err = w.Write(bs); err != nil {
+		return fmt.Errorf("write subscribe: %w", err)
+	}
+
+	return nil
+}
+
+// readSubscribe reads a subscribe message and returns its offset.
+func readSubscribe(r io.Reader) (offset int64, err error) {
+	var bs [len(magic) + 8]byte
+	if _, err = io.ReadFull(r, bs[:]); err != nil {
+		return 0, fmt.Errorf("read subscribe: %w", err)
+	}
+
+	if string(bs[:len(magic)]) != magic {
+		return 0, ErrInvalidHandshake
+	}
+
+	if offset = int64(binary.BigEndian.Uint64(bs[len(magic):])); offset < 0 {
+		return 0, ErrInvalidHandshake
+	}
+
+	return offset, nil
+}
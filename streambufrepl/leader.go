@@ -0,0 +1,134 @@
+package streambufrepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// NewLeader constructs a Leader that replicates b to followers.
+func NewLeader(b *streambuf.Buffer, opts ...Option) (out *Leader) {
+	var l Leader
+	l.b = b
+	l.c = newConfig(opts)
+	l.ctx, l.cancel = context.WithCancel(context.Background())
+	l.listeners = make(map[net.Listener]struct{})
+	return &l
+}
+
+// Leader serves a Buffer to followers. Each follower connection gets its own
+// StreamingReader, so followers replicate independently.
+type Leader struct {
+	mux sync.Mutex
+	wg  sync.WaitGroup
+
+	b *streambuf.Buffer
+	c *config
+
+	// ctx is canceled on Close to stop every session.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	listeners map[net.Listener]struct{}
+
+	closed bool
+}
+
+// Serve accepts follower connections on ln until the Leader is closed, and
+// then returns streambuf.ErrIsClosed. ln is closed when Serve returns.
+func (l *Leader) Serve(ln net.Listener) (err error) {
+	if err = l.track(ln); err != nil {
+		_ = ln.Close()
+		return err
+	}
+	defer l.untrack(ln)
+
+	var conn net.Conn
+	for {
+		if conn, err = ln.Accept(); err != nil {
+			break
+		}
+
+		if !l.startSession() {
+			_ = conn.Close()
+			break
+		}
+
+		go func() {
+			defer l.wg.Done()
+			l.serveConn(conn)
+		}()
+	}
+
+	if l.ctx.Err() != nil {
+		return streambuf.ErrIsClosed
+	}
+
+	return fmt.Errorf("accept follower: %w", err)
+}
+
+// Close stops accepting followers, disconnects existing followers, and waits
+// for their sessions to end.
+func (l *Leader) Close() (err error) {
+	l.mux.Lock()
+	if l.closed {
+		l.mux.Unlock()
+		return streambuf.ErrIsClosed
+	}
+
+	l.closed = true
+	l.cancel()
+	for ln := range l.listeners {
+		_ = ln.Close()
+	}
+
+	l.mux.Unlock()
+	l.wg.Wait()
+	return nil
+}
+
+// serveConn runs a session and logs why it ended.
+func (l *Leader) serveConn(conn net.Conn) {
+	s := newSession(l.ctx, conn, l.b, l.c)
+	if err := s.run(); err != nil && !errors.Is(err, streambuf.ErrIsClosed) {
+		l.c.logger.Warn("streambufrepl: follower session ended", "remote", conn.RemoteAddr().String(), "error", err)
+	}
+}
+
+// startSession counts a new session for Close to wait on, unless the Leader
+// is already closed. Checking closed under the same lock as Close keeps the
+// count from growing once Close has started waiting.
+func (l *Leader) startSession() (ok bool) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if l.closed {
+		return false
+	}
+
+	l.wg.Add(1)
+	return true
+}
+
+// track registers ln so Close can stop it.
+func (l *Leader) track(ln net.Listener) (err error) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if l.closed {
+		return streambuf.ErrIsClosed
+	}
+
+	l.listeners[ln] = struct{}{}
+	return nil
+}
+
+// untrack closes and forgets ln.
+func (l *Leader) untrack(ln net.Listener) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	_ = ln.Close()
+	delete(l.listeners, ln)
+}
@@ -0,0 +1,148 @@
+package streambufrepl
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+func Test_Leader_Serve(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		offset int64
+
+		wantKind    byte
+		wantPayload string
+		wantSize    int64
+	}
+
+	tests := []testcase{
+		{
+			name:        "data from start",
+			offset:      0,
+			wantKind:    kindData,
+			wantPayload: "hello",
+		},
+		{
+			name:        "data from offset",
+			offset:      2,
+			wantKind:    kindData,
+			wantPayload: "llo",
+		},
+		{
+			name:     "heartbeat when idle",
+			offset:   5,
+			wantKind: kindHeartbeat,
+			wantSize: 5,
+		},
+		{
+			name:        "follower ahead",
+			offset:      6,
+			wantKind:    kindError,
+			wantPayload: ErrFollowerAhead.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				conn net.Conn
+				f    frame
+				err  error
+			)
+
+			b := streambuf.NewMemory()
+			t.Cleanup(func() { _ = b.Close() })
+			if _, err = b.Write([]byte("hello")); err != nil {
+				t.Fatal(err)
+			}
+
+			addr := startLeader(t, b)
+			if conn, err = net.Dial("tcp", addr); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+
+			if err = writeSubscribe(conn, tt.offset); err != nil {
+				t.Fatal(err)
+			}
+
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			if err = f.readFrom(conn); err != nil {
+				t.Fatalf("readFrom() unexpected error: %v", err)
+			}
+
+			if f.kind != tt.wantKind {
+				t.Fatalf("readFrom() invalid kind, expected <%c> and received <%c>", tt.wantKind, f.kind)
+			}
+
+			if !strings.HasPrefix(string(f.payload), tt.wantPayload) {
+				t.Fatalf("readFrom() invalid payload, expected <%q> and received <%q>", tt.wantPayload, f.payload)
+			}
+
+			if f.size != tt.wantSize {
+				t.Fatalf("readFrom() invalid size, expected <%d> and received <%d>", tt.wantSize, f.size)
+			}
+		})
+	}
+}
+
+func Test_Leader_Close(t *testing.T) {
+	var (
+		ln  net.Listener
+		err error
+	)
+
+	b := streambuf.NewMemory()
+	t.Cleanup(func() { _ = b.Close() })
+
+	if ln, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLeader(b)
+	done := make(chan error, 1)
+	go func() { done <- l.Serve(ln) }()
+
+	if err = l.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	if err = <-done; !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Serve() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+
+	if err = l.Close(); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Close() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+}
+
+// startLeader serves b on a loopback listener and returns its address.
+func startLeader(t *testing.T, b *streambuf.Buffer) (addr string) {
+	t.Helper()
+	return startLeaderAt(t, b, "127.0.0.1:0")
+}
+
+// startLeaderAt serves b on addr until the test ends and returns the bound address.
+func startLeaderAt(t *testing.T, b *streambuf.Buffer, addr string) (bound string) {
+	t.Helper()
+
+	var (
+		ln  net.Listener
+		err error
+	)
+
+	if ln, err = net.Listen("tcp", addr); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLeader(b, WithHeartbeatInterval(50*time.Millisecond))
+	go func() { _ = l.Serve(ln) }()
+	t.Cleanup(func() { _ = l.Close() })
+	return ln.Addr().String()
+}
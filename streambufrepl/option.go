@@ -0,0 +1,54 @@
+package streambufrepl
+
+import (
+	"log/slog"
+	"net"
+	"time"
+)
+
+// WithHeartbeatInterval sets how often a leader sends heartbeats.
+// Followers must use the same interval, since they reconnect after three
+// missed heartbeats. A non-positive interval uses DefaultHeartbeatInterval.
+func WithHeartbeatInterval(interval time.Duration) (opt Option) {
+	return func(c *config) {
+		if interval <= 0 {
+			interval = DefaultHeartbeatInterval
+		}
+
+		c.heartbeat = interval
+	}
+}
+
+// WithReconnectDelay sets how long a follower waits between connection attempts.
+// A non-positive delay uses DefaultReconnectDelay.
+func WithReconnectDelay(delay time.Duration) (opt Option) {
+	return func(c *config) {
+		if delay <= 0 {
+			delay = DefaultReconnectDelay
+		}
+
+		c.reconnectDelay = delay
+	}
+}
+
+// WithLogger sets the logger that reports failed follower sessions and
+// reconnect attempts. Nothing is logged by default, and nil is ignored.
+func WithLogger(logger *slog.Logger) (opt Option) {
+	return func(c *config) {
+		if logger == nil {
+			return
+		}
+
+		c.logger = logger
+	}
+}
+
+// WithDialer sets the dialer followers use to reach the leader.
+func WithDialer(dialer *net.Dialer) (opt Option) {
+	return func(c *config) {
+		c.dialer = dialer
+	}
+}
+
+// Option configures a Leader or Follower at construction.
+type Option func(c *config)
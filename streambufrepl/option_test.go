@@ -0,0 +1,49 @@
+package streambufrepl
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Option(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		opts []Option
+
+		wantHeartbeat time.Duration
+	}
+
+	tests := []testcase{
+		{
+			name:          "defaults",
+			wantHeartbeat: DefaultHeartbeatInterval,
+		},
+		{
+			name:          "heartbeat interval",
+			opts:          []Option{WithHeartbeatInterval(time.Minute)},
+			wantHeartbeat: time.Minute,
+		},
+		{
+			name:          "nil logger keeps the default",
+			opts:          []Option{WithLogger(nil)},
+			wantHeartbeat: DefaultHeartbeatInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfig(tt.opts)
+			if c.logger == nil {
+				t.Fatal("newConfig() invalid logger, expected a default and received <nil>")
+			}
+
+			// Logging must not panic.
+			c.logger.Info("streambufrepl: test")
+
+			if c.heartbeat != tt.wantHeartbeat {
+				t.Fatalf("newConfig() invalid heartbeat, expected <%v> and received <%v>", tt.wantHeartbeat, c.heartbeat)
+			}
+		})
+	}
+}
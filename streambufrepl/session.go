@@ -0,0 +1,162 @@
+package streambufrepl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// newSession constructs the leader side of one follower connection.
+func newSession(ctx context.Context, conn net.Conn, b *streambuf.Buffer, c *config) (out *session) {
+	var s session
+	s.ctx = ctx
+	s.conn = conn
+	s.b = b
+	s.c = c
+	return &s
+}
+
+// session streams a Buffer to a single follower.
+type session struct {
+	ctx  context.Context
+	conn net.Conn
+
+	b *streambuf.Buffer
+	c *config
+}
+
+// run handles the subscribe message and streams frames until the follower
+// disconnects, the Buffer closes, or the Leader closes.
+func (s *session) run() (err error) {
+	var (
+		offset int64
+		r      io.ReadSeekCloser
+	)
+
+	defer s.conn.Close()
+	stop := context.AfterFunc(s.ctx, func() { _ = s.conn.Close() })
+	defer stop()
+
+	if offset, err = s.subscribe(); err != nil {
+		return err
+	}
+
+	if r, err = s.b.StreamingReader(); err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err = s.seek(r, offset); err != nil {
+		s.sendError(err)
+		return err
+	}
+
+	cr, ok := r.(streambuf.ContextReader)
+	if !ok {
+		err = errors.New("streaming reader does not support contexts")
+		s.sendError(err)
+		return err
+	}
+
+	return s.stream(cr)
+}
+
+// subscribe reads the subscribe message within one follower timeout.
+func (s *session) subscribe() (offset int64, err error) {
+	if err = s.conn.SetReadDeadline(time.Now().Add(s.c.timeout())); err != nil {
+		return 0, fmt.Errorf("set subscribe deadline: %w", err)
+	}
+
+	if offset, err = readSubscribe(s.conn); err != nil {
+		return 0, err
+	}
+
+	if err = s.conn.SetReadDeadline(time.Time{}); err != nil {
+		return 0, fmt.Errorf("clear subscribe deadline: %w", err)
+	}
+
+	return offset, nil
+}
+
+// seek positions r at offset, rejecting offsets past the end of the Buffer.
+func (s *session) seek(r io.Seeker, offset int64) (err error) {
+	var size int64
+	if size, err = s.b.Size(); err != nil {
+		return err
+	}
+
+	if offset > size {
+		return fmt.Errorf("%w: offset %d, leader size %d", ErrFollowerAhead, offset, size)
+	}
+
+	_, err = r.Seek(offset, io.SeekStart)
+	return err
+}
+
+// stream sends data as it is written and a heartbeat every interval. Heartbeats
+// follow a fixed schedule, so LeaderSize stays current while data is flowing.
+func (s *session) stream(r streambuf.ContextReader) (err error) {
+	var (
+		f    frame
+		n    int
+		buf  = make([]byte, 32*1024)
+		next = time.Now().Add(s.c.heartbeat)
+	)
+
+	for {
+		ctx, cancel := context.WithDeadline(s.ctx, next)
+		n, err = r.ReadContext(ctx, buf)
+		cancel()
+
+		switch {
+		case n > 0:
+			f = frame{kind: kindData, payload: buf[:n]}
+			if err = f.writeTo(s.conn); err != nil {
+				return err
+			}
+		case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded):
+		case errors.Is(err, io.EOF):
+			return streambuf.ErrIsClosed
+		case s.ctx.Err() != nil:
+			return streambuf.ErrIsClosed
+		default:
+			s.sendError(err)
+			return err
+		}
+
+		if time.Now().Before(next) {
+			continue
+		}
+
+		next = time.Now().Add(s.c.heartbeat)
+		if f, err = s.heartbeat(); err != nil {
+			return err
+		}
+
+		if err = f.writeTo(s.conn); err != nil {
+			return err
+		}
+	}
+}
+
+// heartbeat returns a heartbeat frame carrying the Buffer size.
+func (s *session) heartbeat() (f frame, err error) {
+	f.kind = kindHeartbeat
+	if f.size, err = s.b.Size(); err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
+
+// sendError reports err to the follower before the connection closes.
+func (s *session) sendError(err error) {
+	f := frame{kind: kindError, payload: []byte(err.Error())}
+	_ = f.writeTo(s.conn)
+}
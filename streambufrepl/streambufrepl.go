@@ -0,0 +1,33 @@
+// Package streambufrepl replicates a streambuf.Buffer from a leader to
+// followers over TCP.
+//
+// # Protocol
+//
+// A follower opens a connection and sends a subscribe message: the four
+// bytes "SBR1" followed by the offset to start from as a big-endian int64.
+// The leader then sends frames until either side disconnects. Each frame is a
+// one-byte kind followed by a kind-specific body:
+//
+//   - 'D' data: a big-endian uint32 length and that many stream bytes, which
+//     continue exactly where the previous data frame ended.
+//   - 'H' heartbeat: the leader's stream size as a big-endian int64, sent
+//     every heartbeat interval, whether or not data is flowing.
+//   - 'E' error: a big-endian uint32 length and a UTF-8 message. The leader
+//     closes the connection after an error frame.
+//
+// A follower that receives nothing for three heartbeat intervals treats the
+// leader as gone and reconnects.
+package streambufrepl
+
+import "errors"
+
+var (
+	// ErrInvalidHandshake is returned when a connection does not start with a
+	// valid subscribe message.
+	ErrInvalidHandshake = errors.New("invalid replication handshake")
+	// ErrFollowerAhead is reported when a follower subscribes past the end of
+	// the leader's stream, meaning the two streams have diverged.
+	ErrFollowerAhead = errors.New("follower is ahead of leader")
+	// ErrInvalidFrame is returned when a follower receives an unknown frame kind.
+	ErrInvalidFrame = errors.New("invalid replication frame")
+)
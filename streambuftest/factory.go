@@ -0,0 +1,11 @@
+package streambuftest
+
+import (
+	"testing"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// Factory constructs an empty backend pair that shares storage.
+// The suite closes both sides; the Factory should register any further cleanup with t.
+type Factory func(t *testing.T) (w streambuf.WritableBackend, r streambuf.ReadableBackend)
@@ -0,0 +1,298 @@
+// Package streambuftest provides a conformance suite for custom streambuf backends.
+package streambuftest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/itsmontoya/streambuf"
+)
+
+// TestBackend runs the WritableBackend and ReadableBackend contract checks
+// against backends constructed by factory.
+func TestBackend(t *testing.T, factory Factory) {
+	type testcase struct {
+		name string
+		fn   func(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend)
+	}
+
+	tests := []testcase{
+		{name: "empty read returns EOF", fn: testEmpty},
+		{name: "write then read", fn: testWriteRead},
+		{name: "read past end returns EOF", fn: testReadPastEnd},
+		{name: "closed readable returns ErrIsClosed", fn: testClosedReadable},
+		{name: "closed writable returns ErrIsClosed", fn: testClosedWritable},
+		{name: "concurrent writes are contiguous", fn: testConcurrentWrites},
+		{name: "truncate keeps offsets", fn: testTruncate},
+		{name: "sync", fn: testSync},
+		{name: "buffer integration", fn: testBuffer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, r := factory(t)
+			t.Cleanup(func() {
+				_ = w.Close()
+				_ = r.Close()
+			})
+
+			tt.fn(t, w, r)
+		})
+	}
+}
+
+func testEmpty(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	var (
+		size int64
+		err  error
+	)
+
+	if size, err = r.Size(); err != nil {
+		t.Fatalf("Size() unexpected error: %v", err)
+	}
+
+	if size != 0 {
+		t.Fatalf("Size() invalid value, expected <0> and received <%d>", size)
+	}
+
+	if _, err = r.ReadAt(make([]byte, 8), 0); !errors.Is(err, io.EOF) {
+		t.Fatalf("ReadAt() invalid error, expected <%v> and received <%v>", io.EOF, err)
+	}
+}
+
+func testWriteRead(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	var (
+		size int64
+		err  error
+	)
+
+	write(t, w, []byte("hello "))
+	write(t, w, []byte("world"))
+
+	if size, err = r.Size(); err != nil {
+		t.Fatalf("Size() unexpected error: %v", err)
+	}
+
+	if size != 11 {
+		t.Fatalf("Size() invalid value, expected <11> and received <%d>", size)
+	}
+
+	if got := readRange(t, r, 0, 11); got != "hello world" {
+		t.Fatalf("ReadAt() invalid value, expected <%q> and received <%q>", "hello world", got)
+	}
+
+	if got := readRange(t, r, 6, 11); got != "world" {
+		t.Fatalf("ReadAt() invalid value, expected <%q> and received <%q>", "world", got)
+	}
+}
+
+func testReadPastEnd(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	var err error
+	write(t, w, []byte("hello"))
+
+	for _, index := range []int64{5, 6, 100} {
+		if _, err = r.ReadAt(make([]byte, 8), index); !errors.Is(err, io.EOF) {
+			t.Fatalf("ReadAt(%d) invalid error, expected <%v> and received <%v>", index, io.EOF, err)
+		}
+	}
+}
+
+func testClosedReadable(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	var err error
+	if err = r.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	if _, err = r.ReadAt(make([]byte, 8), 0); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("ReadAt() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+
+	if _, err = r.Size(); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Size() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+
+	if err = r.Close(); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Close() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+}
+
+func testClosedWritable(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	var err error
+	if err = w.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	if _, err = w.Write([]byte("hello")); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Write() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+
+	if err = w.Truncate(0); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Truncate() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+
+	if err = w.Sync(); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Sync() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+
+	if err = w.Close(); !errors.Is(err, streambuf.ErrIsClosed) {
+		t.Fatalf("Close() invalid error, expected <%v> and received <%v>", streambuf.ErrIsClosed, err)
+	}
+}
+
+func testConcurrentWrites(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	const (
+		writers   = 8
+		writes    = 32
+		chunkSize = 16
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		chunk := bytes.Repeat([]byte{byte('a' + i)}, chunkSize)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < writes; j++ {
+				if _, err := w.Write(chunk); err != nil {
+					t.Errorf("Write() unexpected error: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	got := readRange(t, r, 0, writers*writes*chunkSize)
+	for i := 0; i < len(got); i += chunkSize {
+		chunk := got[i : i+chunkSize]
+		if want := bytes.Repeat([]byte{chunk[0]}, chunkSize); chunk != string(want) {
+			t.Fatalf("ReadAt() interleaved write at index %d: %q", i, chunk)
+		}
+	}
+}
+
+func testTruncate(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	var (
+		size int64
+		err  error
+	)
+
+	write(t, w, []byte("hello "))
+	if err = w.Truncate(6); err != nil {
+		t.Fatalf("Truncate() unexpected error: %v", err)
+	}
+
+	write(t, w, []byte("world"))
+
+	if size, err = r.Size(); err != nil {
+		t.Fatalf("Size() unexpected error: %v", err)
+	}
+
+	if size != 11 {
+		t.Fatalf("Size() invalid value, expected <11> and received <%d>", size)
+	}
+
+	if got := readRange(t, r, 6, 11); got != "world" {
+		t.Fatalf("ReadAt() invalid value, expected <%q> and received <%q>", "world", got)
+	}
+}
+
+func testSync(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	var err error
+	write(t, w, []byte("hello"))
+	if err = w.Sync(); err != nil {
+		t.Fatalf("Sync() unexpected error: %v", err)
+	}
+}
+
+func testBuffer(t *testing.T, w streambuf.WritableBackend, r streambuf.ReadableBackend) {
+	var (
+		b      *streambuf.Buffer
+		reader io.ReadSeekCloser
+		err    error
+	)
+
+	if b, err = streambuf.NewWithBackend(w, r); err != nil {
+		t.Fatalf("NewWithBackend() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = b.Close() })
+
+	if reader, err = b.StreamingReader(); err != nil {
+		t.Fatalf("StreamingReader() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = reader.Close() })
+
+	got := make(chan string, 1)
+	go func() {
+		bs := make([]byte, 5)
+		if _, readErr := io.ReadFull(reader, bs); readErr != nil {
+			got <- fmt.Sprintf("error: %v", readErr)
+			return
+		}
+
+		got <- string(bs)
+	}()
+
+	if _, err = b.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	select {
+	case value := <-got:
+		if value != "hello" {
+			t.Fatalf("Read() invalid value, expected <%q> and received <%q>", "hello", value)
+		}
+	case <-ctx.Done():
+		t.Fatal("Read() did not observe Write()")
+	}
+}
+
+// write writes bs and fails the test on a short write or error.
+func write(t *testing.T, w streambuf.WritableBackend, bs []byte) {
+	t.Helper()
+
+	var (
+		n   int
+		err error
+	)
+
+	if n, err = w.Write(bs); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	if n != len(bs) {
+		t.Fatalf("Write() invalid length, expected <%d> and received <%d>", len(bs), n)
+	}
+}
+
+// readRange reads [start, end) from r, tolerating short reads.
+func readRange(t *testing.T, r streambuf.ReadableBackend, start, end int64) (out string) {
+	t.Helper()
+
+	var (
+		buf bytes.Buffer
+		n   int
+		err error
+	)
+
+	bs := make([]byte, 7)
+	for index := start; index < end; index += int64(n) {
+		if n, err = r.ReadAt(bs[:min(int64(len(bs)), end-index)], index); err != nil {
+			t.Fatalf("ReadAt(%d) unexpected error: %v", index, err)
+		}
+
+		buf.Write(bs[:n])
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,15 @@
+package streambuf
+
+// SyncMode selects when a file Buffer flushes written bytes to stable storage.
+type SyncMode int
+
+const (
+	// SyncNever only syncs when Buffer.Sync is called or the buffer is closed.
+	SyncNever SyncMode = iota
+	// SyncEveryWrite syncs before every Write returns.
+	SyncEveryWrite
+	// SyncEveryBytes syncs once at least SyncPolicy.Bytes unsynced bytes are written.
+	SyncEveryBytes
+	// SyncEveryInterval syncs unsynced bytes every SyncPolicy.Interval.
+	SyncEveryInterval
+)
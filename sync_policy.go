@@ -0,0 +1,41 @@
+package streambuf
+
+import "time"
+
+// SyncPolicy controls the durability of a file Buffer.
+type SyncPolicy struct {
+	// Mode selects when written bytes are synced.
+	Mode SyncMode
+	// Bytes is the unsynced byte threshold for SyncEveryBytes.
+	Bytes int64
+	// Interval is the sync period for SyncEveryInterval.
+	Interval time.Duration
+	// CommittedReads restricts readers to bytes that have been synced.
+	CommittedReads bool
+}
+
+// validate returns an error when the policy is missing a required setting.
+func (p SyncPolicy) validate() (err error) {
+	switch {
+	case p.Mode == SyncEveryBytes && p.Bytes <= 0:
+		return ErrInvalidSyncPolicy
+	case p.Mode == SyncEveryInterval && p.Interval <= 0:
+		return ErrInvalidInterval
+	case p.Mode < SyncNever || p.Mode > SyncEveryInterval:
+		return ErrInvalidSyncPolicy
+	default:
+		return nil
+	}
+}
+
+// shouldSync reports whether pending unsynced bytes require a sync during Write.
+func (p SyncPolicy) shouldSync(pending int64) (ok bool) {
+	switch p.Mode {
+	case SyncEveryWrite:
+		return pending > 0
+	case SyncEveryBytes:
+		return pending >= p.Bytes
+	default:
+		return false
+	}
+}
@@ -0,0 +1,82 @@
+package streambuf
+
+import (
+	"sync"
+)
+
+// newTx constructs an empty transaction on b.
+func newTx(b *Buffer) (out *Tx) {
+	var tx Tx
+	tx.b = b
+	return &tx
+}
+
+// Tx stages writes to a Buffer so they become visible to readers as one unit
+// on Commit. Staged bytes are held in memory and never reach the backend
+// before Commit, so Rollback simply discards them. Staging is unbounded; Len
+// reports how much memory a transaction holds, and large batches should be
+// split across transactions.
+type Tx struct {
+	mux sync.Mutex
+
+	b  *Buffer
+	bs []byte
+
+	done bool
+}
+
+// Write stages a copy of bs. It returns ErrTxDone after Commit or Rollback.
+func (tx *Tx) Write(bs []byte) (n int, err error) {
+	tx.mux.Lock()
+	defer tx.mux.Unlock()
+	if tx.done {
+		return 0, ErrTxDone
+	}
+
+	tx.bs = append(tx.bs, bs...)
+	return len(bs), nil
+}
+
+// Len returns the number of staged bytes.
+func (tx *Tx) Len() (n int) {
+	tx.mux.Lock()
+	defer tx.mux.Unlock()
+	return len(tx.bs)
+}
+
+// Commit appends the staged bytes as a single write that no other write can
+// interleave with. Readers of this Buffer observe either none or all of the
+// bytes, unless the backend write itself fails part way. Other processes
+// reading the same file, such as a NewStream, may observe a partial commit.
+// With WithBackpressure, Commit first waits like Write.
+// It returns ErrTxDone if the transaction already ended and ErrIsClosed if
+// the Buffer has been closed; the staged bytes are discarded either way.
+func (tx *Tx) Commit() (err error) {
+	var bs []byte
+	if bs, err = tx.finish(); err != nil {
+		return err
+	}
+
+	return tx.b.commit(bs)
+}
+
+// Rollback discards the staged bytes.
+// It returns ErrTxDone if the transaction already ended.
+func (tx *Tx) Rollback() (err error) {
+	_, err = tx.finish()
+	return err
+}
+
+// finish ends the transaction and returns the staged bytes.
+func (tx *Tx) finish() (bs []byte, err error) {
+	tx.mux.Lock()
+	defer tx.mux.Unlock()
+	if tx.done {
+		return nil, ErrTxDone
+	}
+
+	tx.done = true
+	bs = tx.bs
+	tx.bs = nil
+	return bs, nil
+}
@@ -0,0 +1,216 @@
+package streambuf
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func Test_Tx(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		init func(t *testing.T) (b *Buffer, err error)
+		// end finishes the transaction.
+		end func(b *Buffer, tx *Tx) (err error)
+
+		want    string
+		wantErr error
+	}
+
+	memoryBuffer := func(t *testing.T) (b *Buffer, err error) {
+		return NewMemory(), nil
+	}
+
+	fileBuffer := func(t *testing.T) (b *Buffer, err error) {
+		return New(t.TempDir() + "/buffer.tmp")
+	}
+
+	commit := func(b *Buffer, tx *Tx) (err error) {
+		return tx.Commit()
+	}
+
+	tests := []testcase{
+		{
+			name: "memory commit",
+			init: memoryBuffer,
+			end:  commit,
+			want: "head:hello world",
+		},
+		{
+			name: "file commit",
+			init: fileBuffer,
+			end:  commit,
+			want: "head:hello world",
+		},
+		{
+			name: "rollback",
+			init: fileBuffer,
+			end: func(b *Buffer, tx *Tx) (err error) {
+				return tx.Rollback()
+			},
+			want: "head:",
+		},
+		{
+			name: "commit twice",
+			init: memoryBuffer,
+			end: func(b *Buffer, tx *Tx) (err error) {
+				if err = tx.Commit(); err != nil {
+					return err
+				}
+
+				return tx.Commit()
+			},
+			want:    "head:hello world",
+			wantErr: ErrTxDone,
+		},
+		{
+			name: "commit after close",
+			init: memoryBuffer,
+			end: func(b *Buffer, tx *Tx) (err error) {
+				_ = b.Close()
+				return tx.Commit()
+			},
+			want:    "head:",
+			wantErr: ErrIsClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.init(t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer b.Close()
+
+			if _, err = b.Write([]byte("head:")); err != nil {
+				t.Fatal(err)
+			}
+
+			tx, err := b.Begin()
+			if err != nil {
+				t.Fatalf("Begin() unexpected error: %v", err)
+			}
+
+			for _, part := range []string{"hello", " ", "world"} {
+				if _, err = tx.Write([]byte(part)); err != nil {
+					t.Fatalf("Write() unexpected error: %v", err)
+				}
+			}
+
+			if size, _ := b.Size(); size != 5 {
+				t.Fatalf("Size() invalid value before commit, expected <5> and received <%d>", size)
+			}
+
+			if err = tt.end(b, tx); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("end invalid error, expected <%v> and received <%v>", tt.wantErr, err)
+			}
+
+			if _, err = tx.Write([]byte("late")); !errors.Is(err, ErrTxDone) {
+				t.Fatalf("Write() invalid error, expected <%v> and received <%v>", ErrTxDone, err)
+			}
+
+			// The backend is read directly since the buffer may be closed.
+			got := make([]byte, 64)
+			n, _ := b.r.ReadAt(got, 0)
+			if string(got[:n]) != tt.want {
+				t.Fatalf("Read() invalid value, expected <%s> and received <%s>", tt.want, got[:n])
+			}
+		})
+	}
+}
+
+func Test_Tx_Commit_atomic_visibility(t *testing.T) {
+	const (
+		commits = 8
+		size    = 64 << 10
+	)
+
+	// The backend appends each write in pieces, like a file write that
+	// readers may observe part way through.
+	w, r := NewMemoryBackend()
+	b, err := NewWithBackend(&splitWriter{WritableBackend: w, parts: 8}, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range commits {
+			tx, err := b.Begin()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			// Stage the unit in several writes of the same byte.
+			part := bytes.Repeat([]byte{byte('a' + i%26)}, size/4)
+			for range 4 {
+				_, _ = tx.Write(part)
+			}
+
+			if err = tx.Commit(); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	rd, err := b.Reader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rd.Close()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		end, err := rd.Seek(0, io.SeekEnd)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if end%size != 0 {
+			t.Fatalf("Seek() observed a partial commit, received size <%d>", end)
+		}
+	}
+}
+
+// splitWriter appends each write in parts, yielding between them.
+type splitWriter struct {
+	WritableBackend
+
+	parts int
+}
+
+func (w *splitWriter) Write(bs []byte) (n int, err error) {
+	step := max(len(bs)/w.parts, 1)
+	for n < len(bs) {
+		var written int
+		if written, err = w.WritableBackend.Write(bs[n:min(n+step, len(bs))]); err != nil {
+			return n, err
+		}
+
+		n += written
+		runtime.Gosched()
+	}
+
+	return n, nil
+}
@@ -0,0 +1,62 @@
+package streambuf
+
+import (
+	"context"
+	"fmt"
+)
+
+// NewTypedBuffer wraps rb so values of T are encoded with codec, one value per record.
+func NewTypedBuffer[T any](rb *RecordBuffer, codec Codec[T]) (out *TypedBuffer[T]) {
+	var tb TypedBuffer[T]
+	tb.rb = rb
+	tb.codec = codec
+	return &tb
+}
+
+// TypedBuffer is an append-only stream of T values built on record framing.
+type TypedBuffer[T any] struct {
+	rb    *RecordBuffer
+	codec Codec[T]
+}
+
+// Append encodes v and appends it as a single record.
+func (tb *TypedBuffer[T]) Append(v T) (err error) {
+	var bs []byte
+	if bs, err = tb.codec.Encode(v); err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+
+	return tb.rb.WriteRecord(bs)
+}
+
+// Reader returns a TypedReader that stops once no complete record remains.
+// It returns ErrIsClosed if the buffer is closed.
+func (tb *TypedBuffer[T]) Reader() (r *TypedReader[T], err error) {
+	var rr *RecordReader
+	if rr, err = tb.rb.Reader(); err != nil {
+		return nil, err
+	}
+
+	return newTypedReader(rr, tb.codec), nil
+}
+
+// StreamingReader returns a TypedReader that waits for future values.
+// It returns ErrIsClosed if the buffer is closed.
+func (tb *TypedBuffer[T]) StreamingReader() (r *TypedReader[T], err error) {
+	var rr *RecordReader
+	if rr, err = tb.rb.StreamingReader(); err != nil {
+		return nil, err
+	}
+
+	return newTypedReader(rr, tb.codec), nil
+}
+
+// Close closes the underlying Buffer.
+func (tb *TypedBuffer[T]) Close() (err error) {
+	return tb.rb.Close()
+}
+
+// CloseAndWait closes the underlying Buffer and waits for readers until ctx is canceled.
+func (tb *TypedBuffer[T]) CloseAndWait(ctx context.Context) (err error) {
+	return tb.rb.CloseAndWait(ctx)
+}
@@ -0,0 +1,166 @@
+package streambuf
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testEvent struct {
+	ID   int
+	Name string
+}
+
+func Test_TypedBuffer_Append(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		codec Codec[testEvent]
+	}
+
+	events := []testEvent{{ID: 1, Name: "joined"}, {ID: 2, Name: "left"}}
+	tests := []testcase{
+		{
+			name:  "json",
+			codec: JSONCodec[testEvent]{},
+		},
+		{
+			name:  "gob",
+			codec: GobCodec[testEvent]{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				tb  *TypedBuffer[testEvent]
+				r   *TypedReader[testEvent]
+				got []testEvent
+				err error
+			)
+
+			tb = NewTypedBuffer(NewRecordBuffer(NewMemory(), true), tt.codec)
+			t.Cleanup(func() {
+				_ = tb.Close()
+			})
+
+			for _, e := range events {
+				if err = tb.Append(e); err != nil {
+					t.Fatalf("Append() unexpected error: %v", err)
+				}
+			}
+
+			if r, err = tb.Reader(); err != nil {
+				t.Fatalf("Reader() unexpected error: %v", err)
+			}
+
+			t.Cleanup(func() {
+				_ = r.Close()
+			})
+
+			for e, gotErr := range r.All() {
+				if gotErr != nil {
+					t.Fatalf("All() unexpected error: %v", gotErr)
+				}
+
+				got = append(got, e)
+			}
+
+			if !reflect.DeepEqual(got, events) {
+				t.Fatalf("All() invalid values, expected <%v> and received <%v>", events, got)
+			}
+		})
+	}
+}
+
+func Test_TypedReader_All_decode_error(t *testing.T) {
+	var (
+		rb   *RecordBuffer
+		tb   *TypedBuffer[testEvent]
+		r    *TypedReader[testEvent]
+		got  []testEvent
+		errs []error
+		err  error
+	)
+
+	rb = NewRecordBuffer(NewMemory(), false)
+	tb = NewTypedBuffer(rb, JSONCodec[testEvent]{})
+	t.Cleanup(func() {
+		_ = tb.Close()
+	})
+
+	if err = rb.WriteRecord([]byte("not json")); err != nil {
+		t.Fatalf("WriteRecord() unexpected error: %v", err)
+	}
+
+	if err = tb.Append(testEvent{ID: 1}); err != nil {
+		t.Fatalf("Append() unexpected error: %v", err)
+	}
+
+	if r, err = tb.Reader(); err != nil {
+		t.Fatalf("Reader() unexpected error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = r.Close()
+	})
+
+	for e, gotErr := range r.All() {
+		if gotErr != nil {
+			errs = append(errs, gotErr)
+			continue
+		}
+
+		got = append(got, e)
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("All() invalid error count, expected <1> and received <%v>", len(errs))
+	}
+
+	if !reflect.DeepEqual(got, []testEvent{{ID: 1}}) {
+		t.Fatalf("All() invalid values after decode error, received <%v>", got)
+	}
+}
+
+func Test_TypedReader_All_closed_reader(t *testing.T) {
+	var (
+		tb  *TypedBuffer[[]byte]
+		r   *TypedReader[[]byte]
+		got []error
+		err error
+	)
+
+	tb = NewTypedBuffer(NewRecordBuffer(NewMemory(), false), BytesCodec{})
+	t.Cleanup(func() {
+		_ = tb.Close()
+	})
+
+	if err = tb.Append([]byte("hello")); err != nil {
+		t.Fatalf("Append() unexpected error: %v", err)
+	}
+
+	if r, err = tb.StreamingReader(); err != nil {
+		t.Fatalf("StreamingReader() unexpected error: %v", err)
+	}
+
+	for v, gotErr := range r.All() {
+		if gotErr != nil {
+			got = append(got, gotErr)
+			continue
+		}
+
+		if string(v) != "hello" {
+			t.Fatalf("All() invalid value, expected <%v> and received <%v>", "hello", string(v))
+		}
+
+		// Closing the reader ends iteration with ErrIsClosed.
+		if err = r.Close(); err != nil {
+			t.Fatalf("Close() unexpected error: %v", err)
+		}
+	}
+
+	if len(got) != 1 || !errors.Is(got[0], ErrIsClosed) {
+		t.Fatalf("All() invalid terminal errors, expected <%v> and received <%v>", ErrIsClosed, got)
+	}
+}
@@ -0,0 +1,85 @@
+package streambuf
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"time"
+)
+
+// newTypedReader constructs a TypedReader that decodes records from rr with codec.
+func newTypedReader[T any](rr *RecordReader, codec Codec[T]) (out *TypedReader[T]) {
+	var tr TypedReader[T]
+	tr.rr = rr
+	tr.codec = codec
+	return &tr
+}
+
+// TypedReader reads T values from a TypedBuffer while tracking its own record boundary.
+type TypedReader[T any] struct {
+	rr    *RecordReader
+	codec Codec[T]
+}
+
+// Read returns the next value.
+// Non-streaming readers return io.EOF when no complete record remains.
+// A record that fails to decode is skipped and its error is returned.
+func (tr *TypedReader[T]) Read() (v T, err error) {
+	var (
+		offset = tr.rr.Offset()
+		bs     []byte
+	)
+
+	if bs, err = tr.rr.ReadRecord(); err != nil {
+		return v, err
+	}
+
+	if v, err = tr.codec.Decode(bs); err != nil {
+		return v, fmt.Errorf("decode record at offset %d: %w", offset, err)
+	}
+
+	return v, nil
+}
+
+// All returns an iterator over the remaining values.
+// Iteration ends at io.EOF. Errors for individual records are yielded and
+// iteration continues past them; any other error is yielded last.
+func (tr *TypedReader[T]) All() (seq iter.Seq2[T, error]) {
+	return func(yield func(T, error) bool) {
+		for {
+			offset := tr.rr.Offset()
+			v, err := tr.Read()
+			switch {
+			case err == io.EOF:
+				return
+			case !yield(v, err):
+				return
+			case err != nil && tr.rr.Offset() == offset:
+				// The reader did not advance, so retrying would repeat the error.
+				return
+			}
+		}
+	}
+}
+
+// Offset returns the absolute offset of the next record boundary.
+func (tr *TypedReader[T]) Offset() (offset int64) {
+	return tr.rr.Offset()
+}
+
+// Seek moves the reader using whence semantics. The resulting position must
+// be a record boundary, such as one previously returned by Offset.
+func (tr *TypedReader[T]) Seek(offset int64, whence int) (pos int64, err error) {
+	return tr.rr.Seek(offset, whence)
+}
+
+// SetReadDeadline bounds Read and All, which return os.ErrDeadlineExceeded
+// once t passes.
+func (tr *TypedReader[T]) SetReadDeadline(t time.Time) (err error) {
+	return tr.rr.SetReadDeadline(t)
+}
+
+// Close closes the reader and unblocks any pending reads.
+func (tr *TypedReader[T]) Close() (err error) {
+	return tr.rr.Close()
+}
@@ -0,0 +1,9 @@
+package streambuf
+
+// viewer is implemented by backends that can expose retained bytes without copying.
+type viewer interface {
+	// viewAt returns up to n bytes starting at index. The returned slice is
+	// never modified by the backend and has no spare capacity. Errors follow
+	// the ReadableBackend ReadAt contract.
+	viewAt(index int64, n int) (out []byte, err error)
+}
@@ -0,0 +1,22 @@
+package streambuf
+
+// WritableBackend is the write side of Buffer storage.
+// Implementations must be safe for concurrent use.
+//
+// Write appends all of bs as one contiguous unit; bytes from concurrent
+// Write calls must never interleave. It returns ErrIsClosed once closed.
+//
+// Truncate may reclaim storage before offset. It must not change the offsets
+// of later bytes, and a backend that cannot reclaim storage may do nothing.
+//
+// Sync flushes written bytes to stable storage. Backends without stable
+// storage return nil.
+//
+// Close releases the backend and returns ErrIsClosed when called again.
+// Truncate, Sync, and Write return ErrIsClosed after Close.
+type WritableBackend interface {
+	Write(bs []byte) (n int, err error)
+	Truncate(offset int64) (err error)
+	Sync() (err error)
+	Close() (err error)
+}
@@ -0,0 +1,117 @@
+package streambuf
+
+import (
+	"sync"
+)
+
+var (
+	_ WritableBackend = &writableChunks{}
+	_ buffersWriter   = &writableChunks{}
+	_ evicter         = &writableChunks{}
+)
+
+// newWritableChunks constructs the chunked writable memory backend used by
+// Buffer when WithChunkSize is set.
+// A capacity greater than 0 bounds the retained bytes, discarding the oldest first.
+func newWritableChunks(size int, capacity int64) (out *writableChunks) {
+	var m writableChunks
+	m.c = newChunks(size)
+	m.capacity = capacity
+	return &m
+}
+
+// writableChunks is a writable memory backend that appends into fixed-size chunks.
+type writableChunks struct {
+	mux sync.RWMutex
+
+	c *chunks
+
+	capacity int64
+
+	closed bool
+}
+
+// Write appends bytes to the backend unless it is closed.
+// Existing bytes are never copied as the backend grows.
+func (m *writableChunks) Write(in []byte) (n int, err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed {
+		return 0, ErrIsClosed
+	}
+
+	m.c.write(in)
+	if m.capacity > 0 {
+		m.c.evict(m.capacity)
+	}
+
+	return len(in), nil
+}
+
+// writeBuffers appends every slice in bufs under a single lock, so readers
+// observe all of them or none.
+func (m *writableChunks) writeBuffers(bufs [][]byte) (n int64, err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed {
+		return 0, ErrIsClosed
+	}
+
+	m.c.write(bufs...)
+	if m.capacity > 0 {
+		m.c.evict(m.capacity)
+	}
+
+	for _, in := range bufs {
+		n += int64(len(in))
+	}
+
+	return n, nil
+}
+
+// evicted returns the absolute offset of the oldest retained byte.
+func (m *writableChunks) evicted() (offset int64) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	if m.closed {
+		return 0
+	}
+
+	return m.c.begin()
+}
+
+// Truncate discards retained bytes before offset.
+func (m *writableChunks) Truncate(offset int64) (err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed {
+		return ErrIsClosed
+	}
+
+	m.c.truncate(offset)
+	return nil
+}
+
+// Sync is a no-op because memory has no stable storage.
+func (m *writableChunks) Sync() (err error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	if m.closed {
+		return ErrIsClosed
+	}
+
+	return nil
+}
+
+// Close marks the writable chunked backend as closed and releases its chunks.
+func (m *writableChunks) Close() (err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed {
+		return ErrIsClosed
+	}
+
+	m.closed = true
+	m.c = nil
+	return nil
+}
@@ -2,16 +2,21 @@ package streambuf
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"sync"
 )
 
-var _ writable = &writableFile{}
+var (
+	_ WritableBackend = &writableFile{}
+	_ buffersWriter   = &writableFile{}
+	_ fileCopier      = &writableFile{}
+)
 
 // newWritableFile constructs a writable file backend for append-only writes.
-func newWritableFile(filepath string) (out *writableFile, err error) {
+func newWritableFile(filepath string, perm os.FileMode) (out *writableFile, err error) {
 	var f writableFile
-	if f.f, err = os.OpenFile(filepath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644); err != nil {
+	if f.f, err = os.OpenFile(filepath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, perm); err != nil {
 		return nil, fmt.Errorf("open writer file: %w", err)
 	}
 
@@ -23,6 +28,9 @@ type writableFile struct {
 	mux sync.RWMutex
 
 	f *os.File
+	// copier is a second handle without O_APPEND, opened by the first copyFrom
+	// and kept until Close.
+	copier *os.File
 
 	closed bool
 }
@@ -38,6 +46,73 @@ func (f *writableFile) Write(bs []byte) (n int, err error) {
 	return f.f.Write(bs)
 }
 
+// writeBuffers appends every slice in bufs with vectored writes where the
+// platform supports them, instead of joining them into one slice first. It
+// holds the write lock so no other append interleaves with the slices.
+func (f *writableFile) writeBuffers(bufs [][]byte) (n int64, err error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if f.closed {
+		return 0, ErrIsClosed
+	}
+
+	return writev(f.f, bufs)
+}
+
+// copyFrom appends up to n bytes from the regular file src through a separate
+// handle without O_APPEND, so the kernel can copy them with copy_file_range.
+// It holds the write lock so no append lands between the seek and the copy.
+func (f *writableFile) copyFrom(src *os.File, n int64) (written int64, err error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	if f.closed {
+		return 0, ErrIsClosed
+	}
+
+	if f.copier == nil {
+		if f.copier, err = os.OpenFile(f.f.Name(), os.O_WRONLY, 0); err != nil {
+			return 0, fmt.Errorf("open writer file: %w", err)
+		}
+	}
+
+	if _, err = f.copier.Seek(0, io.SeekEnd); err != nil {
+		return 0, fmt.Errorf("seek writer file to end: %w", err)
+	}
+
+	if written, err = f.copier.ReadFrom(io.LimitReader(src, n)); err != nil {
+		return written, fmt.Errorf("copy into writer file: %w", err)
+	}
+
+	return written, nil
+}
+
+// Truncate reclaims disk space before offset where the platform supports it.
+// The file size and the offsets of later bytes are unchanged.
+func (f *writableFile) Truncate(offset int64) (err error) {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	if f.closed {
+		return ErrIsClosed
+	}
+
+	return punchHole(f.f, offset)
+}
+
+// Sync flushes written bytes to stable storage.
+func (f *writableFile) Sync() (err error) {
+	f.mux.RLock()
+	defer f.mux.RUnlock()
+	if f.closed {
+		return ErrIsClosed
+	}
+
+	if err = f.f.Sync(); err != nil {
+		return fmt.Errorf("sync writer file: %w", err)
+	}
+
+	return nil
+}
+
 // Close marks the writable file as closed and closes its file handle.
 func (f *writableFile) Close() (err error) {
 	f.mux.Lock()
@@ -48,6 +123,10 @@ func (f *writableFile) Close() (err error) {
 
 	f.closed = true
 
+	if f.copier != nil {
+		_ = f.copier.Close()
+	}
+
 	if err = f.f.Close(); err != nil {
 		return fmt.Errorf("close writer file: %w", err)
 	}
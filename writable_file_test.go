@@ -1,6 +1,7 @@
 package streambuf
 
 import (
+	"bytes"
 	"errors"
 	"os"
 	"testing"
@@ -22,7 +23,7 @@ func Test_writableFile_Close_underlying_file_already_closed(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if w, err = newWritableFile(f.Name()); err != nil {
+	if w, err = newWritableFile(f.Name(), 0644); err != nil {
 		t.Fatal(err)
 	}
 
@@ -39,3 +40,68 @@ func Test_writableFile_Close_underlying_file_already_closed(t *testing.T) {
 		t.Fatalf("Close() invalid error, expected wrapped <%v> and received <%v>", os.ErrClosed, gotErr)
 	}
 }
+
+func Test_writableFile_writeBuffers(t *testing.T) {
+	type testcase struct {
+		name string // description of this test case
+
+		bufs [][]byte
+	}
+
+	many := make([][]byte, 0, 3000)
+	for i := range 3000 {
+		many = append(many, []byte{byte('a' + i%26)})
+	}
+
+	tests := []testcase{
+		{
+			name: "slices",
+			bufs: [][]byte{[]byte("hello"), []byte(" "), []byte("world")},
+		},
+		{
+			name: "empty slices",
+			bufs: [][]byte{nil, []byte("hello"), {}, []byte(" world"), nil},
+		},
+		{
+			name: "more slices than one writev accepts",
+			bufs: many,
+		},
+		{
+			name: "no slices",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				w   *writableFile
+				n   int64
+				got []byte
+				err error
+			)
+
+			path := t.TempDir() + "/buffer.tmp"
+			if w, err = newWritableFile(path, 0644); err != nil {
+				t.Fatal(err)
+			}
+			defer w.Close()
+
+			want := bytes.Join(tt.bufs, nil)
+			if n, err = w.writeBuffers(tt.bufs); err != nil {
+				t.Fatalf("writeBuffers() unexpected error: %v", err)
+			}
+
+			if n != int64(len(want)) {
+				t.Fatalf("writeBuffers() invalid count, expected <%d> and received <%d>", len(want), n)
+			}
+
+			if got, err = os.ReadFile(path); err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Fatalf("writeBuffers() invalid file contents, expected <%q> and received <%q>", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package streambuf
+
+import (
+	"sync"
+)
+
+var _ WritableBackend = &writableJournal{}
+
+// newWritableJournal constructs a writable file backend that journals a
+// checksum for every write. size is the recovered length of the data file.
+func newWritableJournal(w WritableBackend, j *journal, size int64) (out *writableJournal) {
+	var wj writableJournal
+	wj.w = w
+	wj.j = j
+	wj.size = size
+	return &wj
+}
+
+// writableJournal is a write-only file backend that records each write in a journal.
+type writableJournal struct {
+	mux sync.Mutex
+
+	w WritableBackend
+	j *journal
+
+	size int64
+}
+
+// Write appends bytes to the data file and then journals their checksum.
+// Writes are serialized so journal entries stay in data file order.
+func (w *writableJournal) Write(bs []byte) (n int, err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	n, err = w.w.Write(bs)
+	if n == 0 {
+		return n, err
+	}
+
+	w.size += int64(n)
+	var journalErr error
+	if journalErr = w.j.append(journalEntry{end: w.size, crc: checksum(bs[:n])}); err == nil {
+		err = journalErr
+	}
+
+	return n, err
+}
+
+// Truncate reclaims disk space before offset where the platform supports it.
+// The data file is synced and offset is checkpointed as the low-water mark
+// first, so recovery never checksums reclaimed bytes.
+func (w *writableJournal) Truncate(offset int64) (err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	offset = min(offset, w.size)
+	if offset <= w.j.low {
+		return nil
+	}
+
+	if err = w.w.Sync(); err != nil {
+		return err
+	}
+
+	if err = w.j.truncate(w.size, offset); err != nil {
+		return err
+	}
+
+	return w.w.Truncate(offset)
+}
+
+// Sync flushes the data file and then the journal to stable storage.
+// Once enough entries have accumulated, the synced length is checkpointed and
+// the journal is emptied.
+func (w *writableJournal) Sync() (err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if err = w.w.Sync(); err != nil {
+		return err
+	}
+
+	if err = w.j.sync(); err != nil {
+		return err
+	}
+
+	if w.j.count < journalCompactEntries {
+		return nil
+	}
+
+	return w.j.compact(w.size)
+}
+
+// Close closes the data file and the journal.
+func (w *writableJournal) Close() (err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if err = w.w.Close(); err != nil {
+		return err
+	}
+
+	return w.j.close()
+}
@@ -4,16 +4,18 @@ import (
 	"sync"
 )
 
-var _ writable = &writableMemory{}
+var (
+	_ WritableBackend = &writableMemory{}
+	_ buffersWriter   = &writableMemory{}
+	_ evicter         = &writableMemory{}
+)
 
 // newWritableMemory constructs the writable memory backend used by Buffer.
-func newWritableMemory(bs []byte) (out *writableMemory) {
+// A capacity greater than 0 bounds the retained bytes, discarding the oldest first.
+func newWritableMemory(initial int, capacity int64) (out *writableMemory) {
 	var m writableMemory
-	if bs == nil {
-		bs = make([]byte, 0, 1024)
-	}
-
-	m.m = newMemory(bs)
+	m.m = newMemory(make([]byte, 0, initial))
+	m.capacity = capacity
 	return &m
 }
 
@@ -23,6 +25,8 @@ type writableMemory struct {
 
 	m *memory
 
+	capacity int64
+
 	closed bool
 }
 
@@ -38,9 +42,76 @@ func (m *writableMemory) Write(in []byte) (n int, err error) {
 		return append(bs, in...)
 	})
 
+	if m.capacity > 0 {
+		m.m.evict(m.capacity)
+	}
+
 	return len(in), nil
 }
 
+// writeBuffers appends every slice in bufs under a single lock, so readers
+// observe all of them or none.
+func (m *writableMemory) writeBuffers(bufs [][]byte) (n int64, err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed {
+		return 0, ErrIsClosed
+	}
+
+	m.m.write(func(bs []byte) (out []byte) {
+		for _, in := range bufs {
+			bs = append(bs, in...)
+			n += int64(len(in))
+		}
+
+		return bs
+	})
+
+	if m.capacity > 0 {
+		m.m.evict(m.capacity)
+	}
+
+	return n, nil
+}
+
+// evicted returns the absolute offset of the oldest retained byte.
+func (m *writableMemory) evicted() (offset int64) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	if m.closed {
+		return 0
+	}
+
+	m.m.read(func(bs []byte, start int64) {
+		offset = start
+	})
+
+	return offset
+}
+
+// Truncate discards retained bytes before offset.
+func (m *writableMemory) Truncate(offset int64) (err error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if m.closed {
+		return ErrIsClosed
+	}
+
+	m.m.truncate(offset)
+	return nil
+}
+
+// Sync is a no-op because memory has no stable storage.
+func (m *writableMemory) Sync() (err error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+	if m.closed {
+		return ErrIsClosed
+	}
+
+	return nil
+}
+
 // Close marks the writable memory backend as closed and releases its byte slice.
 func (m *writableMemory) Close() (err error) {
 	m.mux.Lock()
@@ -0,0 +1,138 @@
+package streambuf
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+var _ WritableBackend = &writableSegments{}
+
+// newWritableSegments constructs a writable backend that appends to the active
+// segment and rolls over according to cfg.
+func newWritableSegments(s *segments, cfg SegmentConfig) (out *writableSegments, err error) {
+	var w writableSegments
+	w.s = s
+	w.cfg = cfg
+	if w.f, err = openSegmentWriter(s.tail()); err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// writableSegments is a write-only backend spanning a directory of segment files.
+type writableSegments struct {
+	mux sync.Mutex
+
+	s   *segments
+	cfg SegmentConfig
+	f   *os.File
+
+	closed bool
+}
+
+// Write appends bytes to the active segment, rolling over first when required.
+func (w *writableSegments) Write(bs []byte) (n int, err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.closed {
+		return 0, ErrIsClosed
+	}
+
+	if w.cfg.shouldRoll(w.s.tail(), int64(len(bs)), time.Now()) {
+		if err = w.roll(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err = w.f.Write(bs)
+	w.s.grow(int64(n))
+	if err != nil {
+		return n, fmt.Errorf("write segment file: %w", err)
+	}
+
+	return n, nil
+}
+
+// Truncate removes every inactive segment that ends at or before offset.
+func (w *writableSegments) Truncate(offset int64) (err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.closed {
+		return ErrIsClosed
+	}
+
+	return w.s.removeBefore(offset)
+}
+
+// Sync flushes the active segment to stable storage.
+// Inactive segments are synced when they are rolled over.
+func (w *writableSegments) Sync() (err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.closed {
+		return ErrIsClosed
+	}
+
+	if err = w.f.Sync(); err != nil {
+		return fmt.Errorf("sync segment writer file: %w", err)
+	}
+
+	return nil
+}
+
+// Close marks the writable segments as closed and closes the active write handle.
+func (w *writableSegments) Close() (err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if w.closed {
+		return ErrIsClosed
+	}
+
+	w.closed = true
+
+	if err = w.f.Close(); err != nil {
+		return fmt.Errorf("close segment writer file: %w", err)
+	}
+
+	return nil
+}
+
+// roll syncs the active write handle, opens a handle for a new segment, and
+// only then closes the previous handle, so a failed roll leaves the writer
+// appending to the previous segment.
+func (w *writableSegments) roll() (err error) {
+	if err = w.f.Sync(); err != nil {
+		return fmt.Errorf("sync segment writer file: %w", err)
+	}
+
+	var seg *segment
+	if seg, err = w.s.roll(); err != nil {
+		return err
+	}
+
+	var f *os.File
+	if f, err = openSegmentWriter(seg); err != nil {
+		w.s.unroll(seg)
+		return err
+	}
+
+	prev := w.f
+	w.f = f
+	if err = prev.Close(); err != nil {
+		return fmt.Errorf("close segment writer file: %w", err)
+	}
+
+	return nil
+}
+
+// openSegmentWriter opens an append-only write handle for seg.
+func openSegmentWriter(seg *segment) (f *os.File, err error) {
+	if f, err = os.OpenFile(seg.path, os.O_APPEND|os.O_WRONLY, 0644); err != nil {
+		return nil, fmt.Errorf("open segment writer file: %w", err)
+	}
+
+	return f, nil
+}
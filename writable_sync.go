@@ -0,0 +1,93 @@
+package streambuf
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+var _ WritableBackend = &writableSync{}
+
+// newWritableSync wraps w so it syncs according to policy.
+// size is the number of bytes already durable in w.
+func newWritableSync(w WritableBackend, policy SyncPolicy, size int64) (out *writableSync) {
+	var ws writableSync
+	ws.w = w
+	ws.policy = policy
+	ws.written = size
+	ws.committed.Store(size)
+	return &ws
+}
+
+// writableSync is a writable wrapper that applies a SyncPolicy and tracks the
+// committed offset, the end of the bytes that have been synced.
+type writableSync struct {
+	mux sync.Mutex
+
+	w      WritableBackend
+	policy SyncPolicy
+
+	written   int64
+	committed atomic.Int64
+}
+
+// Write appends bytes to the wrapped backend and syncs when the policy requires it.
+func (w *writableSync) Write(bs []byte) (n int, err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	n, err = w.w.Write(bs)
+	w.written += int64(n)
+	if err != nil {
+		return n, err
+	}
+
+	if w.policy.shouldSync(w.pending()) {
+		err = w.sync()
+	}
+
+	return n, err
+}
+
+// Sync flushes unsynced bytes and advances the committed offset.
+func (w *writableSync) Sync() (err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.sync()
+}
+
+// Truncate reclaims space before offset in the wrapped backend.
+func (w *writableSync) Truncate(offset int64) (err error) {
+	return w.w.Truncate(offset)
+}
+
+// Close syncs unsynced bytes and closes the wrapped backend.
+func (w *writableSync) Close() (err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	if err = w.sync(); err != nil {
+		return err
+	}
+
+	return w.w.Close()
+}
+
+// Committed returns the end of the synced bytes.
+func (w *writableSync) Committed() (offset int64) {
+	return w.committed.Load()
+}
+
+func (w *writableSync) sync() (err error) {
+	if w.pending() == 0 {
+		return nil
+	}
+
+	if err = w.w.Sync(); err != nil {
+		return err
+	}
+
+	w.committed.Store(w.written)
+	return nil
+}
+
+func (w *writableSync) pending() (n int64) {
+	return w.written - w.committed.Load()
+}
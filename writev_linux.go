@@ -0,0 +1,87 @@
+//go:build linux
+
+package streambuf
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// iovMax is the largest number of slices a single writev call accepts.
+const iovMax = 1024
+
+// writev appends bufs to f with writev, so the kernel gathers the slices
+// without an intermediate copy. Short writes are resumed where they stopped.
+func writev(f *os.File, bufs [][]byte) (n int64, err error) {
+	var conn syscall.RawConn
+	if conn, err = f.SyscallConn(); err != nil {
+		return 0, fmt.Errorf("writev writer file: %w", err)
+	}
+
+	var (
+		pending = nonEmpty(bufs)
+		iovs    = make([]syscall.Iovec, 0, min(len(pending), iovMax))
+	)
+
+	for len(pending) > 0 {
+		iovs = iovs[:0]
+		for _, bs := range pending[:min(len(pending), iovMax)] {
+			iov := syscall.Iovec{Base: &bs[0]}
+			iov.SetLen(len(bs))
+			iovs = append(iovs, iov)
+		}
+
+		var (
+			written uintptr
+			errno   syscall.Errno
+		)
+
+		if err = conn.Write(func(fd uintptr) (done bool) {
+			written, _, errno = syscall.Syscall(syscall.SYS_WRITEV, fd, uintptr(unsafe.Pointer(&iovs[0])), uintptr(len(iovs)))
+			return errno != syscall.EAGAIN
+		}); err != nil {
+			return n, fmt.Errorf("writev writer file: %w", err)
+		}
+
+		switch errno {
+		case 0:
+		case syscall.EINTR:
+			continue
+		default:
+			return n, fmt.Errorf("writev writer file: %w", os.NewSyscallError("writev", errno))
+		}
+
+		n += int64(written)
+		pending = consume(pending, int64(written))
+	}
+
+	return n, nil
+}
+
+// nonEmpty returns the slices of bufs that hold bytes, without modifying bufs.
+func nonEmpty(bufs [][]byte) (out [][]byte) {
+	out = make([][]byte, 0, len(bufs))
+	for _, bs := range bufs {
+		if len(bs) > 0 {
+			out = append(out, bs)
+		}
+	}
+
+	return out
+}
+
+// consume drops the first n bytes from bufs.
+func consume(bufs [][]byte, n int64) (out [][]byte) {
+	for len(bufs) > 0 && n >= int64(len(bufs[0])) {
+		n -= int64(len(bufs[0]))
+		bufs = bufs[1:]
+	}
+
+	if len(bufs) > 0 {
+		bufs[0] = bufs[0][n:]
+	}
+
+	return bufs
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package streambuf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// writev appends bufs to f as one joined write on platforms without a
+// vectored write path.
+func writev(f *os.File, bufs [][]byte) (n int64, err error) {
+	var written int
+	written, err = f.Write(bytes.Join(bufs, nil))
+	if n = int64(written); err != nil {
+		return n, fmt.Errorf("write writer file: %w", err)
+	}
+
+	return n, nil
+}